@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Backfill checkpoint statuses
+const (
+	checkpointDone   = "done"
+	checkpointEmpty  = "empty" // No cause lists for the date; retried like a failure
+	checkpointFailed = "failed"
+)
+
+// redisBackfillPrefix is the HASH of DateCheckpoint JSON of a backfill scope,
+// keyed by hearing date
+const redisBackfillPrefix = "causelist:backfill:"
+
+// DateCheckpoint records how backfilling one hearing date went
+type DateCheckpoint struct {
+	Date      string    `json:"date"`   // YYYY-MM-DD
+	Status    string    `json:"status"` // done, empty or failed
+	ExitCode  int       `json:"exit_code"`
+	Lists     int       `json:"lists"`
+	Entries   int       `json:"entries"`
+	Attempts  int       `json:"attempts"` // Runs of this date so far
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore keeps the per-date status of backfill runs. A scope keeps
+// the checkpoints of differently filtered backfills apart, see backfillScope
+type CheckpointStore interface {
+	Load(scope string) (map[string]DateCheckpoint, error) // By date
+	Save(scope string, cp DateCheckpoint) error
+}
+
+// newCheckpointStore creates the CheckpointStore selected in the backfill config
+func newCheckpointStore(cfg BackfillConfig) (CheckpointStore, error) {
+	switch cfg.Checkpoint {
+	case "none":
+		return nil, nil
+	case "file":
+		return NewFileCheckpointStore(cfg.CheckpointFile), nil
+	case "redis":
+		if causeListRepo == nil {
+			return nil, errors.New("the redis checkpoint store needs a Redis connection")
+		}
+		return NewRedisCheckpointStore(causeListRepo.conn), nil
+	default:
+		return nil, fmt.Errorf("unknown checkpoint store %q", cfg.Checkpoint)
+	}
+}
+
+// backfillScope names the checkpoints of a backfill: the plain cause list
+// page, or a search with its filters, of a court. Supreme Court scopes have
+// no court prefix so checkpoints from before --court still apply
+func backfillScope(courtID string, opts runOptions) string {
+	scope := "page"
+	if opts.search {
+		scope = fmt.Sprintf("search|type=%s|court=%s|bench=%s",
+			strings.ToLower(opts.listType), strings.ToLower(opts.courtNo), strings.ToLower(opts.bench))
+	}
+	if courtID == supremeCourtID {
+		return scope
+	}
+	return courtID + "|" + scope
+}
+
+// FileCheckpointStore keeps checkpoints in a JSON file
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore creates a FileCheckpointStore; the file is created on the first Save
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// read returns the checkpoints of every scope. A missing file has none
+func (f *FileCheckpointStore) read() (map[string]map[string]DateCheckpoint, error) {
+	all := make(map[string]map[string]DateCheckpoint)
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return all, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoints %s: %w", f.path, err)
+	}
+	return all, nil
+}
+
+// Load returns the checkpoints of scope
+func (f *FileCheckpointStore) Load(scope string) (map[string]DateCheckpoint, error) {
+	all, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	if all[scope] == nil {
+		return make(map[string]DateCheckpoint), nil
+	}
+	return all[scope], nil
+}
+
+// Save writes cp to the file. The file is replaced by a rename so an
+// interrupted write leaves the previous checkpoints intact
+func (f *FileCheckpointStore) Save(scope string, cp DateCheckpoint) error {
+	all, err := f.read()
+	if err != nil {
+		return err
+	}
+	if all[scope] == nil {
+		all[scope] = make(map[string]DateCheckpoint)
+	}
+	all[scope][cp.Date] = cp
+
+	out, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+	return nil
+}
+
+// RedisCheckpointStore keeps checkpoints in Redis, one HASH per scope
+type RedisCheckpointStore struct {
+	conn *RedisConnection
+}
+
+// NewRedisCheckpointStore creates a RedisCheckpointStore on an open connection
+func NewRedisCheckpointStore(conn *RedisConnection) *RedisCheckpointStore {
+	return &RedisCheckpointStore{conn: conn}
+}
+
+// Load returns the checkpoints of scope
+func (r *RedisCheckpointStore) Load(scope string) (map[string]DateCheckpoint, error) {
+	values, err := r.conn.client.HGetAll(ctx, redisBackfillPrefix+scope).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("error reading checkpoints from Redis: %w", err)
+	}
+	checkpoints := make(map[string]DateCheckpoint, len(values))
+	for date, value := range values {
+		var cp DateCheckpoint
+		if err := json.Unmarshal([]byte(value), &cp); err != nil {
+			return nil, fmt.Errorf("error unmarshalling checkpoint of %s: %v", date, err)
+		}
+		checkpoints[date] = cp
+	}
+	return checkpoints, nil
+}
+
+// Save writes cp to the HASH of scope
+func (r *RedisCheckpointStore) Save(scope string, cp DateCheckpoint) error {
+	val, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("error marshalling checkpoint: %v", err)
+	}
+	if err := r.conn.client.HSet(ctx, redisBackfillPrefix+scope, cp.Date, val).Err(); err != nil {
+		return fmt.Errorf("error saving checkpoint to Redis: %w", err)
+	}
+	return nil
+}
+
+// courtDays drops the weekends and holidays of the backfill config from dates
+func courtDays(dates []time.Time, cfg BackfillConfig) []time.Time {
+	holidays := make(map[string]bool, len(cfg.Holidays))
+	for _, h := range cfg.Holidays {
+		holidays[h] = true
+	}
+	var kept []time.Time
+	for _, date := range dates {
+		if cfg.SkipWeekends && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+			continue
+		}
+		if holidays[date.Format("2006-01-02")] {
+			continue
+		}
+		kept = append(kept, date)
+	}
+	return kept
+}
+
+// pendingDates drops the dates a previous run of the scope finished
+func pendingDates(dates []time.Time, checkpoints map[string]DateCheckpoint) []time.Time {
+	var pending []time.Time
+	for _, date := range dates {
+		if checkpoints[date.Format("2006-01-02")].Status != checkpointDone {
+			pending = append(pending, date)
+		}
+	}
+	return pending
+}
+
+// newDateCheckpoint records the outcome of running date after prev. A date
+// that found no cause lists is empty, not done, so the next run tries it again
+func newDateCheckpoint(date time.Time, prev DateCheckpoint, code int, lists int, entries int) DateCheckpoint {
+	cp := DateCheckpoint{
+		Date:      date.Format("2006-01-02"),
+		Status:    checkpointDone,
+		ExitCode:  code,
+		Lists:     lists,
+		Entries:   entries,
+		Attempts:  prev.Attempts + 1,
+		UpdatedAt: time.Now(),
+	}
+	switch {
+	case code != exitOK:
+		cp.Status = checkpointFailed
+	case lists == 0:
+		cp.Status = checkpointEmpty
+	}
+	return cp
+}
+
+// logCheckpoints prints the dates a backfill still has to retry
+func logCheckpoints(checkpoints map[string]DateCheckpoint) {
+	var failed, empty []string
+	for date, cp := range checkpoints {
+		switch cp.Status {
+		case checkpointFailed:
+			failed = append(failed, date)
+		case checkpointEmpty:
+			empty = append(empty, date)
+		}
+	}
+	sort.Strings(failed)
+	sort.Strings(empty)
+	if len(failed) > 0 {
+		log.Printf("Backfill: %d dates failed and will be retried on the next run: %s", len(failed), strings.Join(failed, ", "))
+	}
+	if len(empty) > 0 {
+		log.Printf("Backfill: %d dates had no cause lists and will be retried on the next run (past dates need --search): %s", len(empty), strings.Join(empty, ", "))
+	}
+}
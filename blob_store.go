@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBlobNotFound is returned when a key does not exist in a BlobStore
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobInfo describes an object held in a BlobStore
+type BlobInfo struct {
+	Key          string            // Key relative to the store root
+	Size         int64             // Size in bytes
+	ContentType  string            // MIME type the object was stored with
+	LastModified time.Time         // Time of the last Put
+	Metadata     map[string]string // User metadata stored with the object
+}
+
+// PutOptions controls how an object is written
+type PutOptions struct {
+	ContentType string            // MIME type, guessed by the store when empty
+	Public      bool              // Whether the object is a public artifact, see S3BlobStore
+	Metadata    map[string]string // User metadata to store with the object
+}
+
+// User metadata keys of archived artifacts
+const (
+	blobMetaSHA256      = "sha256"       // Hex SHA-256 of the content
+	blobMetaFetchedAt   = "fetched-at"   // RFC 3339 time of the download
+	blobMetaSourceURL   = "source-url"   // Where the content was downloaded from
+	blobMetaHearingDate = "hearing-date" // YYYY-MM-DD hearing date of the cause list
+)
+
+// BlobStore is the storage backend for archived artifacts
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (BlobInfo, error)
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// blobStore is the store the scraper archives to, set up from appConfig.Storage
+var blobStore BlobStore
+
+// newBlobStore creates the BlobStore selected in the storage config
+func newBlobStore(cfg StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "s3":
+		return NewS3BlobStore(appConfig.AWS)
+	case "local":
+		return NewLocalBlobStore(cfg.LocalDir)
+	case "memory":
+		return NewMemoryBlobStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// cleanBlobKey normalizes a key to a slash separated path without a leading "./" or "/"
+func cleanBlobKey(key string) string {
+	key = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(key)), "/")
+	if key == "." {
+		return ""
+	}
+	return key
+}
+
+// LocalBlobStore keeps objects as files under a root directory. Content type
+// and metadata are kept in a ".meta.json" file next to each object
+type LocalBlobStore struct {
+	root string
+}
+
+const localMetaSuffix = ".meta.json"
+
+// NewLocalBlobStore creates a LocalBlobStore rooted at dir, creating it if needed
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve blob store directory: %w", err)
+	}
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create blob store directory: %w", err)
+	}
+	return &LocalBlobStore{root: root}, nil
+}
+
+// path returns the file of a key. A key whose ".." segments lead outside the
+// root, e.g. one built from page data, is rejected
+func (l *LocalBlobStore) path(key string) (string, error) {
+	path := filepath.Join(l.root, filepath.FromSlash(cleanBlobKey(key)))
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("blob key %q is outside the store", key)
+	}
+	return path, nil
+}
+
+// Put writes the object and its metadata file
+func (l *LocalBlobStore) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = getMimeType(key)
+	}
+	meta, err := json.Marshal(localBlobMeta{ContentType: contentType, Metadata: opts.Metadata})
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata for %s: %w", key, err)
+	}
+	return os.WriteFile(path+localMetaSuffix, meta, 0644)
+}
+
+// localBlobMeta is the content of a ".meta.json" file
+type localBlobMeta struct {
+	ContentType string            `json:"content_type"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Get opens the object for reading
+func (l *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	return file, err
+}
+
+// Stat returns the size, content type and metadata of the object
+func (l *LocalBlobStore) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return BlobInfo{}, fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	} else if err != nil {
+		return BlobInfo{}, err
+	}
+
+	info := BlobInfo{
+		Key:          cleanBlobKey(key),
+		Size:         fi.Size(),
+		ContentType:  getMimeType(key),
+		LastModified: fi.ModTime(),
+	}
+	if raw, err := os.ReadFile(path + localMetaSuffix); err == nil {
+		var meta localBlobMeta
+		if err := json.Unmarshal(raw, &meta); err == nil {
+			info.ContentType = meta.ContentType
+			info.Metadata = meta.Metadata
+		}
+	}
+	return info, nil
+}
+
+// List returns every object whose key starts with prefix, sorted by key
+func (l *LocalBlobStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	var infos []BlobInfo
+	err := filepath.WalkDir(l.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, localMetaSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := l.Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, info)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blob store: %w", err)
+	}
+	return infos, nil
+}
+
+// Delete removes the object and its metadata file
+func (l *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	} else if err != nil {
+		return err
+	}
+	os.Remove(path + localMetaSuffix)
+	return nil
+}
+
+// SignedURL returns a file:// URL; local files need no signing
+func (l *LocalBlobStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+// MemoryBlobStore keeps objects in memory, for tests and dry runs
+type MemoryBlobStore struct {
+	mu      sync.RWMutex
+	objects map[string]memoryBlob
+}
+
+type memoryBlob struct {
+	data []byte
+	info BlobInfo
+}
+
+// NewMemoryBlobStore creates an empty MemoryBlobStore
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{objects: make(map[string]memoryBlob)}
+}
+
+// Put stores a copy of body under key
+func (m *MemoryBlobStore) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	key = cleanBlobKey(key)
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = getMimeType(key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = memoryBlob{
+		data: data,
+		info: BlobInfo{
+			Key:          key,
+			Size:         int64(len(data)),
+			ContentType:  contentType,
+			LastModified: time.Now(),
+			Metadata:     opts.Metadata,
+		},
+	}
+	return nil
+}
+
+// Get returns a reader over the stored bytes
+func (m *MemoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.objects[cleanBlobKey(key)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(blob.data)), nil
+}
+
+// Stat returns the info recorded at Put time
+func (m *MemoryBlobStore) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.objects[cleanBlobKey(key)]
+	if !ok {
+		return BlobInfo{}, fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	return blob.info, nil
+}
+
+// List returns every object whose key starts with prefix, sorted by key
+func (m *MemoryBlobStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var infos []BlobInfo
+	for key, blob := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			infos = append(infos, blob.info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos, nil
+}
+
+// Delete removes the object
+func (m *MemoryBlobStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key = cleanBlobKey(key)
+	if _, ok := m.objects[key]; !ok {
+		return fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+// SignedURL returns a memory:// URL that only identifies the object
+func (m *MemoryBlobStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := m.Stat(ctx, key); err != nil {
+		return "", err
+	}
+	return "memory://" + cleanBlobKey(key), nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBlobStores(t *testing.T) {
+	stores := []struct {
+		name string
+		new  func(t *testing.T) BlobStore
+	}{
+		{"local", func(t *testing.T) BlobStore {
+			store, err := NewLocalBlobStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return store
+		}},
+		{"memory", func(t *testing.T) BlobStore { return NewMemoryBlobStore() }},
+	}
+	for _, tt := range stores {
+		t.Run(tt.name, func(t *testing.T) {
+			testBlobStore(t, tt.new(t))
+		})
+	}
+}
+
+// testBlobStore puts, reads, lists and deletes objects of a store
+func testBlobStore(t *testing.T, store BlobStore) {
+	ctx := context.Background()
+	objects := []struct {
+		key         string
+		body        string
+		opts        PutOptions
+		contentType string
+	}{
+		{"causelist/pdf/supreme_court/page.html", "<html></html>", PutOptions{
+			ContentType: "text/html; charset=utf-8",
+			Metadata:    map[string]string{blobMetaSHA256: "abc", blobMetaSourceURL: "https://www.sci.gov.in/cause-list/"},
+		}, "text/html; charset=utf-8"},
+		{"/causelist/pdf/supreme_court/2024-10-16/M_J_1.pdf", "%PDF-1.7", PutOptions{}, "application/pdf"},
+		{"other/data.json", "{}", PutOptions{ContentType: "application/json"}, "application/json"},
+	}
+	for _, o := range objects {
+		if err := store.Put(ctx, o.key, strings.NewReader(o.body), o.opts); err != nil {
+			t.Fatalf("Put %s: %v", o.key, err)
+		}
+	}
+
+	for _, o := range objects {
+		body, err := store.Get(ctx, o.key)
+		if err != nil {
+			t.Fatalf("Get %s: %v", o.key, err)
+		}
+		data, err := io.ReadAll(body)
+		body.Close()
+		if err != nil || string(data) != o.body {
+			t.Errorf("Get %s = %q, %v, want %q", o.key, data, err, o.body)
+		}
+
+		info, err := store.Stat(ctx, o.key)
+		if err != nil {
+			t.Fatalf("Stat %s: %v", o.key, err)
+		}
+		if info.Key != cleanBlobKey(o.key) || info.Size != int64(len(o.body)) {
+			t.Errorf("Stat %s: key %q, size %d", o.key, info.Key, info.Size)
+		}
+		if !strings.HasPrefix(info.ContentType, strings.Split(o.contentType, ";")[0]) {
+			t.Errorf("Stat %s: content type %q, want %q", o.key, info.ContentType, o.contentType)
+		}
+		if len(o.opts.Metadata) > 0 && !maps.Equal(info.Metadata, o.opts.Metadata) {
+			t.Errorf("Stat %s: metadata %v, want %v", o.key, info.Metadata, o.opts.Metadata)
+		}
+	}
+
+	infos, err := store.List(ctx, "causelist/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, info := range infos {
+		keys = append(keys, info.Key)
+	}
+	slices.Sort(keys)
+	want := []string{"causelist/pdf/supreme_court/2024-10-16/M_J_1.pdf", "causelist/pdf/supreme_court/page.html"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("List = %v, want %v", keys, want)
+	}
+
+	if err := store.Delete(ctx, "other/data.json"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "other/data.json"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get after Delete: %v, want ErrBlobNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "other/data.json"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Stat after Delete: %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, "other/data.json"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("second Delete: %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestLocalBlobStoreRejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		key    string
+		reject bool
+	}{
+		{"../outside.html", true},
+		{"causelist/../../outside.html", true},
+		{"..", true},
+		{"causelist/../inside.html", false},
+		{"causelist/..pdf/name..html", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := store.Put(ctx, tt.key, strings.NewReader("x"), PutOptions{})
+			if tt.reject {
+				if err == nil {
+					t.Fatalf("Put %q outside the store succeeded", tt.key)
+				}
+				if _, err := store.Get(ctx, tt.key); err == nil {
+					t.Errorf("Get %q outside the store succeeded", tt.key)
+				}
+				if _, err := store.Stat(ctx, tt.key); err == nil {
+					t.Errorf("Stat %q outside the store succeeded", tt.key)
+				}
+				if err := store.Delete(ctx, tt.key); err == nil {
+					t.Errorf("Delete %q outside the store succeeded", tt.key)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Put %q: %v", tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// CaseListing says where and when a matter is listed: an entry together with
+// the cause list it was found in
+type CaseListing struct {
+	CauseListEntry
+	DateOfHearing string // Hearing date of the cause list
+	ListType      string // List type, see getDescription
+	PDFLink       string // Source PDF
+	Court         string // Court ID, see Court
+}
+
+// maxIndexedRange is the largest "000123 - 000130" case number range whose
+// every number is indexed; longer ranges only index their first number
+const maxIndexedRange = 100
+
+// caseNumberPattern splits a case number such as "SLP(C) No. 000123 - 000125/2024"
+// or "SLPC-123-2024" into case type, first number, last number and year
+var caseNumberPattern = regexp.MustCompile(`(?i)^(.*?)\s*(?:no\.?)?\s*-?\s*(\d+)(?:\s*-\s*(\d+))?\s*[/-]\s*(\d{4})$`)
+
+// nonLetters matches everything that is dropped from a normalized case type
+var nonLetters = regexp.MustCompile(`[^A-Z]`)
+
+// normalizeCaseNumbers returns the index keys of a case number, e.g.
+// "SLP(C) No. 000123 - 000124/2024" gives "SLPC-123-2024" and "SLPC-124-2024".
+// It returns nil when the case number cannot be parsed
+func normalizeCaseNumbers(caseNo string) []string {
+	m := caseNumberPattern.FindStringSubmatch(strings.TrimSpace(caseNo))
+	if m == nil {
+		return nil
+	}
+	caseType := nonLetters.ReplaceAllString(strings.ToUpper(m[1]), "")
+	first, err := strconv.Atoi(m[2])
+	if err != nil {
+		return nil
+	}
+	last := first
+	if m[3] != "" {
+		if n, err := strconv.Atoi(m[3]); err == nil && n >= first && n-first < maxIndexedRange {
+			last = n
+		}
+	}
+
+	var keys []string
+	for n := first; n <= last; n++ {
+		keys = append(keys, fmt.Sprintf("%s-%d-%s", caseType, n, m[4]))
+	}
+	return keys
+}
+
+// normalizeCaseNumber returns the single index key of a case number, or ""
+func normalizeCaseNumber(caseNo string) string {
+	keys := normalizeCaseNumbers(caseNo)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// diaryNumberPattern matches "12345-2024", "12345/2024" or "Diary No. 12345/2024"
+var diaryNumberPattern = regexp.MustCompile(`(\d+)\s*[/-]\s*(\d{4})\s*$`)
+
+// normalizeDiaryNumber returns the index key of a diary number, e.g. "12345-2024", or ""
+func normalizeDiaryNumber(diaryNo string) string {
+	m := diaryNumberPattern.FindStringSubmatch(strings.TrimSpace(diaryNo))
+	if m == nil {
+		return ""
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%s", n, m[2])
+}
+
+// entryCaseNumberKeys returns the case number index keys of an entry
+func entryCaseNumberKeys(entry CauseListEntry) []string {
+	if entry.CaseNoMap != "" {
+		return normalizeCaseNumbers(entry.CaseNoMap)
+	}
+	return normalizeCaseNumbers(entry.CaseNo)
+}
+
+// newCaseListing builds the listing of an entry found in causeList
+func newCaseListing(entry CauseListEntry, causeList CauseList) CaseListing {
+	return CaseListing{
+		CauseListEntry: entry,
+		DateOfHearing:  causeList.DateOfHearing.String(),
+		ListType:       causeList.Description,
+		PDFLink:        causeList.PDFLink,
+		Court:          causeList.CourtID(),
+	}
+}
+
+// sortCaseListings orders listings by hearing date, list type and item number
+func sortCaseListings(listings []CaseListing) {
+	sort.SliceStable(listings, func(i, j int) bool {
+		a, b := listings[i], listings[j]
+		if a.DateOfHearing != b.DateOfHearing {
+			return a.DateOfHearing < b.DateOfHearing
+		}
+		if a.ListType != b.ListType {
+			return a.ListType < b.ListType
+		}
+		return compareItemNumbers(a.Sno, b.Sno) < 0
+	})
+}
+
+// runLookup implements the "lookup" command: is a matter listed, and where
+func runLookup(args []string) int {
+	fs := flag.NewFlagSet("lookup", flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	caseNo := fs.String("case", "", "case number, e.g. \"SLP(C) No. 123/2024\"")
+	diaryNo := fs.String("diary", "", "diary number, e.g. 12345/2024")
+	date := fs.String("date", "", "only listings on this hearing date")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if (*caseNo == "") == (*diaryNo == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --case or --diary is required")
+		return exitUsage
+	}
+
+	var hearingDate string
+	if *date != "" {
+		d, err := parseDateFlag(*date, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		hearingDate = d.Format("2006-01-02")
+	}
+
+	c, err := cf.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	appConfig = c
+
+	var listings []CaseListing
+	if appConfig.Database.DSN != "" {
+		store, err := openCauseListStore()
+		if err != nil {
+			log.Printf("Failed to set up database: %v", err)
+			return exitFailure
+		}
+		if *caseNo != "" {
+			listings, err = store.LookupCase(*caseNo, hearingDate)
+		} else {
+			listings, err = store.LookupDiary(*diaryNo, hearingDate)
+		}
+		if err != nil {
+			log.Printf("Lookup failed: %v", err)
+			return exitFailure
+		}
+	} else {
+		conn, err := NewRedisConnection(appConfig.Redis)
+		if err != nil {
+			log.Printf("Failed to connect to Redis: %v", err)
+			return exitFailure
+		}
+		repo := NewCauseListRepository(conn, appConfig.Redis)
+		if *caseNo != "" {
+			listings, err = repo.LookupCase(*caseNo, hearingDate)
+		} else {
+			listings, err = repo.LookupDiary(*diaryNo, hearingDate)
+		}
+		if err != nil {
+			log.Printf("Lookup failed: %v", err)
+			return exitFailure
+		}
+	}
+
+	if len(listings) == 0 {
+		fmt.Println("Not listed")
+		return exitNotListed
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tLIST\tCOURT\tITEM\tCASE\tBENCH\tPDF")
+	for _, l := range listings {
+		caseLabel := l.CaseNo
+		if caseLabel == "" {
+			caseLabel = "Diary No. " + l.DiaryNo
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", l.DateOfHearing, l.ListType, l.CourtNo, l.Sno, caseLabel, l.JudgeName(), l.PDFLink)
+	}
+	w.Flush()
+	return exitOK
+}
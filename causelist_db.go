@@ -0,0 +1,653 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// CauseListRecord is a listing document, i.e. one cause list PDF
+type CauseListRecord struct {
+	ID            uint        `gorm:"primaryKey"`
+	PDFID         string      `gorm:"size:255;not null;uniqueIndex"` // trimPDFLink of the PDF
+	DateOfHearing HearingDate `gorm:"type:date;index"`               // NULL when the cause list has no date
+	Description   string      `gorm:"size:128;not null;index"`       // List type, see getDescription
+	PDFLink       string      `gorm:"not null"`
+	Court         string      `gorm:"size:32;not null;default:sci;index"` // Court ID, see Court
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	Entries []CauseListEntryRecord `gorm:"foreignKey:CauseListID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName keeps the table name stable if the struct is renamed
+func (CauseListRecord) TableName() string {
+	return "cause_lists"
+}
+
+// CauseListEntryRecord is a matter listed in a cause list. Rows are unique on
+// court + hearing date + list type + case key so re-running a date never
+// duplicates them and the same case listed by two courts is kept twice
+type CauseListEntryRecord struct {
+	ID             uint        `gorm:"primaryKey"`
+	CauseListID    uint        `gorm:"not null;index"`                                            // Source PDF
+	Court          string      `gorm:"size:32;not null;uniqueIndex:idx_entry_listing,priority:1"` // Court ID of the cause list
+	DateOfHearing  HearingDate `gorm:"type:date;not null;uniqueIndex:idx_entry_listing,priority:2"`
+	ListType       string      `gorm:"size:128;not null;uniqueIndex:idx_entry_listing,priority:3"`
+	CaseKey        string      `gorm:"size:255;not null;uniqueIndex:idx_entry_listing,priority:4"` // See entryCaseKey
+	Sno            string      `gorm:"size:32"`
+	CaseNo         string      `gorm:"size:255"`
+	DiaryNo        string      `gorm:"size:64;index"`
+	CaseNoMap      string      `gorm:"size:255;index"`
+	JudgeName      string      // Judges joined with ", ", for searching
+	CourtNo        string      `gorm:"size:64"`
+	CaseNumberKeys []string    `gorm:"type:jsonb;serializer:json"` // Every number of a range, see entryCaseNumberKeys
+	DiaryKey       string      `gorm:"size:64;index"`              // See normalizeDiaryNumber
+
+	Judges              []string `gorm:"type:jsonb;serializer:json"`
+	Section             string   `gorm:"size:255"`
+	Petitioner          string
+	Respondent          string
+	PetitionerAdvocates []string `gorm:"type:jsonb;serializer:json"`
+	RespondentAdvocates []string `gorm:"type:jsonb;serializer:json"`
+	IANumbers           []string `gorm:"column:ia_numbers;type:jsonb;serializer:json"`
+	ConnectedTo         string   `gorm:"size:32"` // Item number of the main matter
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName keeps the table name stable if the struct is renamed
+func (CauseListEntryRecord) TableName() string {
+	return "cause_list_entries"
+}
+
+// migration is one step of the database schema
+type migration struct {
+	ID string
+	Up func(tx *gorm.DB) error
+}
+
+// schemaMigration records an applied migration
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey;size:128"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migrations are applied in order and each only once. Never edit an applied
+// migration; append a new one instead. Each step is plain DDL so the schema at
+// any step can be read off the list; the names of tables, constraints and
+// indexes are the ones GORM's AutoMigrate gave them, which earlier versions
+// used, so their databases carry on from where they are
+var migrations = []migration{
+	{
+		ID: "0001_create_cause_lists",
+		Up: ddl(
+			`CREATE TABLE cause_lists (
+				id bigserial PRIMARY KEY,
+				pdf_id varchar(255) NOT NULL,
+				date_of_hearing varchar(32) NOT NULL,
+				description varchar(128) NOT NULL,
+				pdf_link text NOT NULL,
+				created_at timestamptz,
+				updated_at timestamptz
+			)`,
+			`CREATE UNIQUE INDEX idx_cause_lists_pdf_id ON cause_lists (pdf_id)`,
+			`CREATE INDEX idx_cause_lists_date_of_hearing ON cause_lists (date_of_hearing)`,
+			`CREATE INDEX idx_cause_lists_description ON cause_lists (description)`,
+			`CREATE TABLE cause_list_entries (
+				id bigserial PRIMARY KEY,
+				cause_list_id bigint NOT NULL,
+				date_of_hearing varchar(32) NOT NULL,
+				list_type varchar(128) NOT NULL,
+				case_key varchar(255) NOT NULL,
+				sno varchar(32),
+				case_no varchar(255),
+				diary_no varchar(64),
+				case_no_map varchar(255),
+				judge_name text,
+				court_no varchar(64),
+				created_at timestamptz,
+				updated_at timestamptz,
+				CONSTRAINT fk_cause_lists_entries FOREIGN KEY (cause_list_id) REFERENCES cause_lists (id) ON DELETE CASCADE
+			)`,
+			`CREATE INDEX idx_cause_list_entries_cause_list_id ON cause_list_entries (cause_list_id)`,
+			`CREATE UNIQUE INDEX idx_entry_listing ON cause_list_entries (date_of_hearing, list_type, case_key)`,
+			`CREATE INDEX idx_cause_list_entries_diary_no ON cause_list_entries (diary_no)`,
+			`CREATE INDEX idx_cause_list_entries_case_no_map ON cause_list_entries (case_no_map)`,
+		),
+	},
+	{
+		ID: "0002_add_case_lookup_keys",
+		Up: ddl(
+			`ALTER TABLE cause_list_entries ADD COLUMN case_number_key varchar(255), ADD COLUMN diary_key varchar(64)`,
+			`CREATE INDEX idx_cause_list_entries_case_number_key ON cause_list_entries (case_number_key)`,
+			`CREATE INDEX idx_cause_list_entries_diary_key ON cause_list_entries (diary_key)`,
+		),
+	},
+	{
+		ID: "0003_create_watchlist_items",
+		Up: ddl(
+			`CREATE TABLE watchlist_items (
+				id bigserial PRIMARY KEY,
+				match_key varchar(255) NOT NULL,
+				case_no varchar(255),
+				diary_no varchar(64),
+				owner varchar(255),
+				tags text,
+				created_at timestamptz,
+				updated_at timestamptz
+			)`,
+			`CREATE UNIQUE INDEX idx_watchlist_items_match_key ON watchlist_items (match_key)`,
+			`CREATE INDEX idx_watchlist_items_owner ON watchlist_items (owner)`,
+		),
+	},
+	{
+		ID: "0004_add_entry_details",
+		Up: ddl(
+			`ALTER TABLE cause_list_entries
+				ADD COLUMN judges jsonb,
+				ADD COLUMN section varchar(255),
+				ADD COLUMN petitioner text,
+				ADD COLUMN respondent text,
+				ADD COLUMN petitioner_advocates jsonb,
+				ADD COLUMN respondent_advocates jsonb,
+				ADD COLUMN ia_numbers jsonb,
+				ADD COLUMN connected_to varchar(32)`,
+		),
+	},
+	{
+		ID: "0005_add_cause_list_court",
+		Up: ddl(
+			`ALTER TABLE cause_lists ADD COLUMN court varchar(32) NOT NULL DEFAULT 'sci'`,
+			`CREATE INDEX idx_cause_lists_court ON cause_lists (court)`,
+		),
+	},
+	{
+		// Entries of different courts with the same date, list type and case
+		// overwrote each other
+		ID: "0006_add_court_to_entry_key",
+		Up: ddl(
+			`ALTER TABLE cause_list_entries ADD COLUMN court varchar(32) NOT NULL DEFAULT 'sci'`,
+			`UPDATE cause_list_entries SET court = cause_lists.court
+				FROM cause_lists WHERE cause_lists.id = cause_list_entries.cause_list_id`,
+			`DROP INDEX idx_entry_listing`,
+			`CREATE UNIQUE INDEX idx_entry_listing ON cause_list_entries (court, date_of_hearing, list_type, case_key)`,
+		),
+	},
+	{
+		// case_number_key held only the first number of a range such as
+		// "C.A. 1-3/2024"; rows saved before keep only that one until their
+		// date is run again
+		ID: "0007_add_case_number_keys",
+		Up: ddl(
+			`ALTER TABLE cause_list_entries ADD COLUMN case_number_keys jsonb NOT NULL DEFAULT '[]'`,
+			`UPDATE cause_list_entries SET case_number_keys = jsonb_build_array(case_number_key)
+				WHERE case_number_key IS NOT NULL AND case_number_key <> ''`,
+			`CREATE INDEX idx_cause_list_entries_case_number_keys ON cause_list_entries USING gin (case_number_keys jsonb_path_ops)`,
+			`DROP INDEX idx_cause_list_entries_case_number_key`,
+			`ALTER TABLE cause_list_entries DROP COLUMN case_number_key`,
+		),
+	},
+	{
+		// Hearing dates were text, "" when a cause list had no date. Entries
+		// of undated cause lists are no longer saved
+		ID: "0008_date_of_hearing_as_date",
+		Up: ddl(
+			`ALTER TABLE cause_lists ALTER COLUMN date_of_hearing DROP NOT NULL,
+				ALTER COLUMN date_of_hearing TYPE date USING NULLIF(date_of_hearing, '')::date`,
+			`DELETE FROM cause_list_entries WHERE date_of_hearing = ''`,
+			`ALTER TABLE cause_list_entries ALTER COLUMN date_of_hearing TYPE date USING date_of_hearing::date`,
+		),
+	},
+}
+
+// ddl returns a migration step that executes the statements in order
+func ddl(statements ...string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CauseListStore persists cause lists and their entries to PostgreSQL
+type CauseListStore struct {
+	db *gorm.DB
+}
+
+// causeListStore is nil when no database is configured
+var causeListStore *CauseListStore
+
+// NewCauseListStore connects to the database described by cfg
+func NewCauseListStore(cfg DatabaseConfig) (*CauseListStore, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return &CauseListStore{db: db}, nil
+}
+
+// Migrate applies every migration that has not been applied yet
+func (s *CauseListStore) Migrate() error {
+	if err := s.db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		var count int64
+		if err := s.db.Model(&schemaMigration{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", m.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.ID, err)
+		}
+		log.Printf("Applied migration %s", m.ID)
+	}
+	return nil
+}
+
+// SaveCauseLists upserts the listing documents keyed on their PDF ID and
+// returns the database ID of each one
+func (s *CauseListStore) SaveCauseLists(causeLists map[string]CauseList) (map[string]uint, error) {
+	var ids map[string]uint
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		ids, err = saveCauseLists(tx, causeLists)
+		return err
+	})
+	return ids, err
+}
+
+// saveCauseLists upserts the listing documents in tx
+func saveCauseLists(tx *gorm.DB, causeLists map[string]CauseList) (map[string]uint, error) {
+	ids := make(map[string]uint, len(causeLists))
+	for pdfID, causeList := range causeLists {
+		record := CauseListRecord{
+			PDFID:         pdfID,
+			DateOfHearing: causeList.DateOfHearing,
+			Description:   causeList.Description,
+			PDFLink:       causeList.PDFLink,
+			Court:         causeList.CourtID(),
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "pdf_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"date_of_hearing", "description", "pdf_link", "court", "updated_at"}),
+		}).Create(&record).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to save cause list %s: %w", pdfID, err)
+		}
+		ids[pdfID] = record.ID
+	}
+	return ids, nil
+}
+
+// SaveEntries upserts the listed matters of causeLists. Entries are keyed on
+// court + hearing date + list type + case key, so saving the same run twice is
+// a no-op. Entries of causeLists that are no longer listed, e.g. after a
+// corrected PDF was re-uploaded, are deleted. Everything is saved in one
+// transaction, so a failure leaves the previous entries as they were
+func (s *CauseListStore) SaveEntries(causeLists map[string]CauseList, entries []CauseListEntry) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return saveEntries(tx, causeLists, entries)
+	})
+}
+
+// saveEntries saves the cause lists and their entries in tx, see SaveEntries
+func saveEntries(tx *gorm.DB, causeLists map[string]CauseList, entries []CauseListEntry) error {
+	ids, err := saveCauseLists(tx, causeLists)
+	if err != nil {
+		return err
+	}
+
+	// Postgres rejects an upsert that touches the same row twice, so keep
+	// only the last entry for each key
+	byKey := make(map[string]int)
+	undated := make(map[string]bool)
+	var records []CauseListEntryRecord
+	for _, entry := range entries {
+		causeList, ok := causeLists[entry.PDFID]
+		caseKey := entryCaseKey(entry)
+		if !ok || caseKey == "" {
+			continue
+		}
+		if causeList.DateOfHearing.IsZero() {
+			// Entries are keyed on their hearing date; the cause list itself
+			// is saved with no date
+			if !undated[entry.PDFID] {
+				log.Printf("Not saving the entries of %s to the database: it has no hearing date", entry.PDFID)
+				undated[entry.PDFID] = true
+			}
+			continue
+		}
+		record := newEntryRecord(ids[entry.PDFID], causeList, caseKey, entry)
+		key := record.Court + "|" + record.DateOfHearing.String() + "|" + record.ListType + "|" + record.CaseKey
+		if i, seen := byKey[key]; seen {
+			records[i] = record
+			continue
+		}
+		byKey[key] = len(records)
+		records = append(records, record)
+	}
+	if err := pruneEntries(tx, ids, records); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	err = tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "court"}, {Name: "date_of_hearing"}, {Name: "list_type"}, {Name: "case_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"cause_list_id", "sno", "case_no", "diary_no", "case_no_map", "judge_name", "court_no",
+			"case_number_keys", "diary_key", "judges", "section", "petitioner", "respondent",
+			"petitioner_advocates", "respondent_advocates", "ia_numbers", "connected_to", "updated_at",
+		}),
+	}).CreateInBatches(records, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to save cause list entries: %w", err)
+	}
+	return nil
+}
+
+// pruneEntries deletes the entries of the cause lists ids that are not in records
+func pruneEntries(tx *gorm.DB, ids map[string]uint, records []CauseListEntryRecord) error {
+	kept := make(map[uint][]string, len(ids))
+	for _, id := range ids {
+		kept[id] = nil
+	}
+	for _, record := range records {
+		kept[record.CauseListID] = append(kept[record.CauseListID], record.CaseKey)
+	}
+	for id, caseKeys := range kept {
+		query := tx.Where("cause_list_id = ?", id)
+		if len(caseKeys) > 0 {
+			query = query.Where("case_key NOT IN ?", caseKeys)
+		}
+		if err := query.Delete(&CauseListEntryRecord{}).Error; err != nil {
+			return fmt.Errorf("failed to delete unlisted cause list entries: %w", err)
+		}
+	}
+	return nil
+}
+
+// newEntryRecord builds the row of an entry of causeList. Like the Redis case
+// index, it keeps every case number of a range, so a lookup of any of them
+// finds the entry
+func newEntryRecord(causeListID uint, causeList CauseList, caseKey string, entry CauseListEntry) CauseListEntryRecord {
+	caseNumberKeys := entryCaseNumberKeys(entry)
+	if caseNumberKeys == nil {
+		caseNumberKeys = []string{}
+	}
+	return CauseListEntryRecord{
+		CauseListID:    causeListID,
+		Court:          causeList.CourtID(),
+		DateOfHearing:  causeList.DateOfHearing,
+		ListType:       causeList.Description,
+		CaseKey:        caseKey,
+		Sno:            entry.Sno,
+		CaseNo:         entry.CaseNo,
+		DiaryNo:        entry.DiaryNo,
+		CaseNoMap:      entry.CaseNoMap,
+		JudgeName:      entry.JudgeName(),
+		CourtNo:        entry.CourtNo,
+		CaseNumberKeys: caseNumberKeys,
+		DiaryKey:       normalizeDiaryNumber(entry.DiaryNo),
+
+		Judges:              entry.Judges,
+		Section:             entry.Section,
+		Petitioner:          entry.Petitioner,
+		Respondent:          entry.Respondent,
+		PetitionerAdvocates: entry.PetitionerAdvocates,
+		RespondentAdvocates: entry.RespondentAdvocates,
+		IANumbers:           entry.IANumbers,
+		ConnectedTo:         entry.ConnectedTo,
+	}
+}
+
+// hasCaseNumberKey is the condition of entries with a case number key among
+// their case_number_keys; use it with caseNumberKeyArg
+const hasCaseNumberKey = "cause_list_entries.case_number_keys @> ?::jsonb"
+
+// caseNumberKeyArg returns the JSON array hasCaseNumberKey matches a key with
+func caseNumberKeyArg(key string) string {
+	arg, _ := json.Marshal([]string{key})
+	return string(arg)
+}
+
+// entryCaseKey identifies the matter of an entry: the formatted case number,
+// or the diary number for unregistered matters
+func entryCaseKey(entry CauseListEntry) string {
+	switch {
+	case entry.CaseNoMap != "":
+		return entry.CaseNoMap
+	case entry.CaseNo != "":
+		return entry.CaseNo
+	case entry.DiaryNo != "":
+		return "diary:" + entry.DiaryNo
+	default:
+		return ""
+	}
+}
+
+// LookupCase returns the listings of a case number, optionally only on one
+// hearing date (YYYY-MM-DD)
+func (s *CauseListStore) LookupCase(caseNo, date string) ([]CaseListing, error) {
+	caseKey := normalizeCaseNumber(caseNo)
+	if caseKey == "" {
+		return nil, fmt.Errorf("cannot parse case number %q", caseNo)
+	}
+	return s.lookup(hasCaseNumberKey, caseNumberKeyArg(caseKey), date)
+}
+
+// LookupDiary returns the listings of a diary number, optionally only on one
+// hearing date (YYYY-MM-DD)
+func (s *CauseListStore) LookupDiary(diaryNo, date string) ([]CaseListing, error) {
+	diaryKey := normalizeDiaryNumber(diaryNo)
+	if diaryKey == "" {
+		return nil, fmt.Errorf("cannot parse diary number %q", diaryNo)
+	}
+	return s.lookup("cause_list_entries.diary_key = ?", diaryKey, date)
+}
+
+// lookup returns the listings of the entries matching where
+func (s *CauseListStore) lookup(where string, key, date string) ([]CaseListing, error) {
+	query := s.entriesQuery().Where(where, key)
+	if date != "" {
+		query = query.Where("cause_list_entries.date_of_hearing = ?", date)
+	}
+	listings, err := s.findListings(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", key, err)
+	}
+	return listings, nil
+}
+
+// entriesQuery selects entries together with the PDF of their cause list
+func (s *CauseListStore) entriesQuery() *gorm.DB {
+	return s.db.Table("cause_list_entries").
+		Joins("JOIN cause_lists ON cause_lists.id = cause_list_entries.cause_list_id")
+}
+
+// findListings runs an entriesQuery and returns its rows as sorted listings
+func (s *CauseListStore) findListings(query *gorm.DB) ([]CaseListing, error) {
+	var rows []struct {
+		CauseListEntryRecord
+		PDFID   string
+		PDFLink string
+	}
+	err := query.Select("cause_list_entries.*, cause_lists.pdf_id, cause_lists.pdf_link").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	listings := make([]CaseListing, 0, len(rows))
+	for _, row := range rows {
+		entry := row.entry()
+		entry.PDFID = row.PDFID
+		listings = append(listings, CaseListing{
+			CauseListEntry: entry,
+			DateOfHearing:  row.DateOfHearing.String(),
+			ListType:       row.ListType,
+			PDFLink:        row.PDFLink,
+			Court:          row.Court,
+		})
+	}
+	sortCaseListings(listings)
+	return listings, nil
+}
+
+// CauseLists returns a page of the cause lists of a hearing date, or of every
+// date when date is empty
+func (s *CauseListStore) CauseLists(date string, page Page) ([]CauseListDocument, int, error) {
+	query := s.db.Model(&CauseListRecord{})
+	if date != "" {
+		query = query.Where("date_of_hearing = ?", date)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count cause lists: %w", err)
+	}
+
+	var records []CauseListRecord
+	err := query.Order("date_of_hearing, description, pdf_id").
+		Offset(page.Offset).Limit(page.Limit).Find(&records).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read cause lists: %w", err)
+	}
+	docs := make([]CauseListDocument, 0, len(records))
+	for _, record := range records {
+		docs = append(docs, record.document())
+	}
+	return docs, int(total), nil
+}
+
+// CauseList returns a cause list by document ID (see causeListDocumentID) and its entries
+func (s *CauseListStore) CauseList(id string) (*CauseListDocument, []CaseListing, error) {
+	pdfID, ok := pdfIDOfDocument(id)
+	if !ok {
+		return nil, nil, fmt.Errorf("cause list %s: %w", id, ErrNotFound)
+	}
+	var record CauseListRecord
+	err := s.db.Where("pdf_id = ?", pdfID).Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("cause list %s: %w", id, ErrNotFound)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cause list %s: %w", id, err)
+	}
+
+	listings, err := s.findListings(s.entriesQuery().Where("cause_list_entries.cause_list_id = ?", record.ID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read entries of %s: %w", id, err)
+	}
+	doc := record.document()
+	return &doc, listings, nil
+}
+
+// entryOrder sorts entries like sortCaseListings: by hearing date, list type
+// and item number. Item numbers are text, so "12.1" is ordered by its item
+// and sub-item numerically; ones that are not numbers come last
+const entryOrder = `cause_list_entries.date_of_hearing, cause_list_entries.list_type, ` +
+	`substring(cause_list_entries.sno from '^[0-9]+')::int, ` +
+	`substring(cause_list_entries.sno from '^[0-9]+\.([0-9]+)')::int, cause_list_entries.sno`
+
+// Entries returns a page of the entries matching filter
+func (s *CauseListStore) Entries(filter EntryFilter, page Page) ([]CaseListing, int, error) {
+	query := s.entriesQuery()
+	if filter.CaseNo != "" {
+		query = query.Where(hasCaseNumberKey, caseNumberKeyArg(normalizeCaseNumber(filter.CaseNo)))
+	}
+	if filter.DiaryNo != "" {
+		query = query.Where("cause_list_entries.diary_key = ?", normalizeDiaryNumber(filter.DiaryNo))
+	}
+	if filter.Court != "" {
+		query = query.Where("LOWER(cause_list_entries.court_no) = LOWER(?)", filter.Court)
+	}
+	if filter.Judge != "" {
+		query = query.Where("cause_list_entries.judge_name ILIKE ?", "%"+filter.Judge+"%")
+	}
+	if filter.Date != "" {
+		query = query.Where("cause_list_entries.date_of_hearing = ?", filter.Date)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count entries: %w", err)
+	}
+	query = query.Order(entryOrder).
+		Offset(page.Offset).Limit(page.Limit)
+	listings, err := s.findListings(query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read entries: %w", err)
+	}
+	return listings, int(total), nil
+}
+
+// Ping checks that the database is reachable
+func (s *CauseListStore) Ping() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Ping()
+}
+
+// entry converts a record back to the entry it was saved from, without its PDF ID
+func (r CauseListEntryRecord) entry() CauseListEntry {
+	return CauseListEntry{
+		Sno:                 r.Sno,
+		CaseNo:              r.CaseNo,
+		DiaryNo:             r.DiaryNo,
+		CaseNoMap:           r.CaseNoMap,
+		Judges:              r.Judges,
+		CourtNo:             r.CourtNo,
+		Section:             r.Section,
+		Petitioner:          r.Petitioner,
+		Respondent:          r.Respondent,
+		PetitionerAdvocates: r.PetitionerAdvocates,
+		RespondentAdvocates: r.RespondentAdvocates,
+		IANumbers:           r.IANumbers,
+		ConnectedTo:         r.ConnectedTo,
+	}
+}
+
+// document converts a record to the document shape stored in Redis
+func (r CauseListRecord) document() CauseListDocument {
+	causeList := CauseList{
+		DateOfHearing: r.DateOfHearing,
+		Description:   r.Description,
+		PDFLink:       r.PDFLink,
+		Court:         r.Court,
+	}
+	return CauseListDocument{
+		ID:        causeListDocumentID(r.PDFID, causeList),
+		PDFID:     r.PDFID,
+		CauseList: causeList,
+	}
+}
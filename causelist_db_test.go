@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewEntryRecordKeepsRangedCaseNumbers(t *testing.T) {
+	pdfID := "2024-10-17/M_J_1"
+	causeList := CauseList{
+		DateOfHearing: hearingDateOf(time.Date(2024, 10, 17, 0, 0, 0, 0, istLocation)),
+		Description:   "Miscellaneous",
+	}
+	entry := CauseListEntry{
+		PDFID:   pdfID,
+		Sno:     "12",
+		CaseNo:  "SLP(C) No. 000123 - 000125/2024",
+		DiaryNo: "4567-2024",
+	}
+
+	record := newEntryRecord(7, causeList, entryCaseKey(entry), entry)
+	want := []string{"SLPC-123-2024", "SLPC-124-2024", "SLPC-125-2024"}
+	if !slices.Equal(record.CaseNumberKeys, want) {
+		t.Fatalf("case number keys %v, want %v", record.CaseNumberKeys, want)
+	}
+
+	// LookupCase of the middle number matches by containment of its argument
+	var arg []string
+	if err := json.Unmarshal([]byte(caseNumberKeyArg(normalizeCaseNumber("SLP(C) No. 124/2024"))), &arg); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range arg {
+		if !slices.Contains(record.CaseNumberKeys, key) {
+			t.Errorf("lookup key %q not among %v", key, record.CaseNumberKeys)
+		}
+	}
+}
+
+func TestNewEntryRecordWithoutCaseNumber(t *testing.T) {
+	record := newEntryRecord(1, CauseList{}, "diary:1-2024", CauseListEntry{DiaryNo: "1-2024"})
+	if record.CaseNumberKeys == nil || len(record.CaseNumberKeys) != 0 {
+		t.Errorf("case number keys %#v, want an empty list", record.CaseNumberKeys)
+	}
+}
+
+func TestHearingDateColumn(t *testing.T) {
+	date := hearingDateOf(time.Date(2024, 10, 17, 0, 0, 0, 0, istLocation))
+	if value, err := date.Value(); err != nil || value != "2024-10-17" {
+		t.Errorf("Value() = %v, %v, want 2024-10-17", value, err)
+	}
+	if value, err := (HearingDate{}).Value(); err != nil || value != nil {
+		t.Errorf("Value() of no date = %v, %v, want NULL", value, err)
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"date", time.Date(2024, 10, 17, 0, 0, 0, 0, time.UTC), "2024-10-17"},
+		{"text", "2024-10-17", "2024-10-17"},
+		{"bytes", []byte("2024-10-17"), "2024-10-17"},
+		{"null", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := date
+			if err := got.Scan(tt.value); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("scanned %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
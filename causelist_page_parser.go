@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PageSelectors locate the listing table of a court's cause list page. The
+// defaults are in CourtInfo; scraper.page_selectors overrides them per court
+type PageSelectors struct {
+	Table  string `yaml:"table,omitempty"`  // The listing table, the first match is used
+	Header string `yaml:"header,omitempty"` // Header cells of the table, one per column
+	Row    string `yaml:"row,omitempty"`    // Rows of the table holding cause lists
+	Cell   string `yaml:"cell,omitempty"`   // Cells of a row, one per column
+	Link   string `yaml:"link,omitempty"`   // PDF links of a row
+
+	// Columns read by name: the first header containing the text, in any case
+	DateColumn  string `yaml:"date_column,omitempty"`  // Hearing date, required
+	TitleColumn string `yaml:"title_column,omitempty"` // List title, empty = not read
+}
+
+// merge returns s with the fields set in override replaced
+func (s PageSelectors) merge(override PageSelectors) PageSelectors {
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	set(&s.Table, override.Table)
+	set(&s.Header, override.Header)
+	set(&s.Row, override.Row)
+	set(&s.Cell, override.Cell)
+	set(&s.Link, override.Link)
+	set(&s.DateColumn, override.DateColumn)
+	set(&s.TitleColumn, override.TitleColumn)
+	return s
+}
+
+// pageSelectors returns the selectors of a court's cause list page
+func pageSelectors(courtID string) PageSelectors {
+	return courtInfo(courtID).Selectors.merge(appConfig.Scraper.PageSelectors[courtID])
+}
+
+// LayoutError reports a cause list page that does not have the structure its
+// selectors describe, usually because the court changed its site
+type LayoutError struct {
+	Court    string
+	Selector string // Selector or column that did not match
+	Problem  string
+}
+
+func (e *LayoutError) Error() string {
+	return fmt.Sprintf("%s cause list page layout changed: %s (%s); check scraper.page_selectors", e.Court, e.Problem, e.Selector)
+}
+
+// ListingRow is one row of a listing table that links to cause list PDFs
+type ListingRow struct {
+	Columns map[string]string // Text of every cell, by header
+	Date    string            // Text of the date column
+	Title   string            // Text of the title column, "" when not read
+	Links   []string          // PDF links, absolute
+}
+
+// parseListingTable reads the listing table of a court's cause list page.
+// Rows without a PDF link are skipped, so an empty list is not an error; a
+// missing table, header, column or cell is a *LayoutError
+func parseListingTable(courtID, pageURL string, body []byte) ([]ListingRow, error) {
+	sel := pageSelectors(courtID)
+	layoutError := func(selector, format string, args ...any) error {
+		return &LayoutError{Court: courtID, Selector: selector, Problem: fmt.Sprintf(format, args...)}
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page URL: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	table := doc.Find(sel.Table).First()
+	if table.Length() == 0 {
+		return nil, layoutError(sel.Table, "no listing table")
+	}
+	var headers []string
+	table.Find(sel.Header).Each(func(_ int, th *goquery.Selection) {
+		headers = append(headers, cellText(th))
+	})
+	if len(headers) == 0 {
+		return nil, layoutError(sel.Header, "the listing table has no header")
+	}
+	column := func(name string) (int, error) {
+		for i, header := range headers {
+			if strings.Contains(strings.ToLower(header), strings.ToLower(name)) {
+				return i, nil
+			}
+		}
+		return -1, layoutError(name, "no column in header %q", strings.Join(headers, " | "))
+	}
+	dateColumn, err := column(sel.DateColumn)
+	if err != nil {
+		return nil, err
+	}
+	titleColumn := -1
+	if sel.TitleColumn != "" {
+		if titleColumn, err = column(sel.TitleColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []ListingRow
+	var rowErr error
+	table.Find(sel.Row).EachWithBreak(func(i int, tr *goquery.Selection) bool {
+		var links []string
+		tr.Find(sel.Link).Each(func(_ int, a *goquery.Selection) {
+			href, _ := a.Attr("href")
+			if link, err := base.Parse(strings.TrimSpace(href)); err == nil && href != "" {
+				links = append(links, link.String())
+			}
+		})
+		if len(links) == 0 {
+			return true
+		}
+		cells := tr.Find(sel.Cell)
+		if cells.Length() != len(headers) {
+			rowErr = layoutError(sel.Cell, "row %d has %d cells for %d columns", i+1, cells.Length(), len(headers))
+			return false
+		}
+		row := ListingRow{Columns: make(map[string]string, len(headers)), Links: links}
+		cells.Each(func(j int, td *goquery.Selection) {
+			row.Columns[headers[j]] = cellText(td)
+		})
+		row.Date = row.Columns[headers[dateColumn]]
+		if titleColumn >= 0 {
+			row.Title = row.Columns[headers[titleColumn]]
+		}
+		rows = append(rows, row)
+		return true
+	})
+	if rowErr != nil {
+		return nil, rowErr
+	}
+	if len(rows) == 0 {
+		log.Printf("The %s cause list page lists no PDFs", courtID)
+	}
+	return rows, nil
+}
+
+// cellText returns the text of a cell, nested elements included, with its
+// whitespace collapsed
+func cellText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
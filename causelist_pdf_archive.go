@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// pdfArchivePrefix is where cause list PDFs are archived in the blob store,
+// next to the archived cause list pages, in the archive directory of their court
+const pdfArchivePrefix = "causelist/pdf/"
+
+// pdfArchiveDir returns the blob store prefix of the versions of a PDF, e.g.
+// "causelist/pdf/supreme_court/2024-10-16/M_R_2/" for the Supreme Court PDF
+// ID "2024-10-16/M_R_2" (see trimPDFLink)
+func pdfArchiveDir(pdfID string, causeList CauseList) string {
+	return cleanBlobKey(pdfArchivePrefix+courtInfo(causeList.CourtID()).ArchiveDir+"/"+pdfID) + "/"
+}
+
+// pdfArchiveKey returns the blob store key of one content version of a PDF,
+// named by its SHA-256, so a re-uploaded PDF never replaces an earlier version
+func pdfArchiveKey(pdfID string, causeList CauseList, hash string) string {
+	return pdfArchiveDir(pdfID, causeList) + hash + ".pdf"
+}
+
+// legacyPDFArchiveKey is where a PDF was archived before versions were kept,
+// e.g. "causelist/pdf/supreme_court/2024-10-16/M_R_2.pdf"
+func legacyPDFArchiveKey(pdfID string, causeList CauseList) string {
+	return strings.TrimSuffix(pdfArchiveDir(pdfID, causeList), "/") + ".pdf"
+}
+
+// latestArchivedPDF returns the key of the version of a PDF archived last,
+// or its legacy key when no version was archived
+func latestArchivedPDF(ctx context.Context, pdfID string, causeList CauseList) (string, error) {
+	infos, err := blobStore.List(ctx, pdfArchiveDir(pdfID, causeList))
+	if err != nil {
+		return "", err
+	}
+	var latest *BlobInfo
+	for i, info := range infos {
+		if strings.HasSuffix(info.Key, ".pdf") && (latest == nil || info.LastModified.After(latest.LastModified)) {
+			latest = &infos[i]
+		}
+	}
+	if latest == nil {
+		return legacyPDFArchiveKey(pdfID, causeList), nil
+	}
+	return latest.Key, nil
+}
+
+// archivePDF stores a downloaded PDF in the blob store with its checksum,
+// fetch time, source link and hearing date. Errors are a *StorageError
+func archivePDF(ctx context.Context, pdfID string, causeList CauseList, pdf *downloadedPDF) error {
+	key := pdfArchiveKey(pdfID, causeList, pdf.hash)
+	err := blobStore.Put(ctx, key, bytes.NewReader(pdf.data), PutOptions{
+		ContentType: "application/pdf",
+		Metadata: map[string]string{
+			blobMetaSHA256:      pdf.hash,
+			blobMetaFetchedAt:   pdf.fetchedAt.UTC().Format(time.RFC3339),
+			blobMetaSourceURL:   causeList.PDFLink,
+			blobMetaHearingDate: causeList.DateOfHearing.String(),
+		},
+	})
+	if err != nil {
+		return &StorageError{Op: "archive", Key: key, Err: err}
+	}
+	return nil
+}
+
+// readArchivedPDF reads the latest version of a PDF back from the blob store.
+// The content is checked against the stored checksum, and the fetch time is
+// the one of the original download. Errors are a *StorageError
+func readArchivedPDF(ctx context.Context, pdfID string, causeList CauseList) (*downloadedPDF, error) {
+	key, err := latestArchivedPDF(ctx, pdfID, causeList)
+	if err != nil {
+		return nil, &StorageError{Op: "read", Key: pdfArchiveDir(pdfID, causeList), Err: err}
+	}
+	info, err := blobStore.Stat(ctx, key)
+	if err != nil {
+		return nil, &StorageError{Op: "read", Key: key, Err: err}
+	}
+	body, err := blobStore.Get(ctx, key)
+	if err != nil {
+		return nil, &StorageError{Op: "read", Key: key, Err: err}
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, &StorageError{Op: "read", Key: key, Err: err}
+	}
+
+	sum := sha256.Sum256(data)
+	pdf := &downloadedPDF{data: data, hash: hex.EncodeToString(sum[:]), fetchedAt: info.LastModified}
+	if want := info.Metadata[blobMetaSHA256]; want != "" && want != pdf.hash {
+		return nil, &StorageError{Op: "verify", Key: key, Err: fmt.Errorf("content does not match checksum %s", want)}
+	}
+	if fetchedAt, err := time.Parse(time.RFC3339, info.Metadata[blobMetaFetchedAt]); err == nil {
+		pdf.fetchedAt = fetchedAt
+	}
+	return pdf, nil
+}
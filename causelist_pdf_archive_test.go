@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestArchivePDFKeepsEveryVersion(t *testing.T) {
+	saved := blobStore
+	defer func() { blobStore = saved }()
+	blobStore = NewMemoryBlobStore()
+
+	ctx := context.Background()
+	pdfID := "2024-10-16/M_R_2"
+	causeList := CauseList{PDFLink: "https://example.org/2024-10-16/M_R_2.pdf", Court: supremeCourtID}
+	var keys []string
+	for _, content := range []string{"first upload", "corrected upload"} {
+		sum := sha256.Sum256([]byte(content))
+		pdf := &downloadedPDF{data: []byte(content), hash: hex.EncodeToString(sum[:]), fetchedAt: time.Now()}
+		if err := archivePDF(ctx, pdfID, causeList, pdf); err != nil {
+			t.Fatalf("archivePDF: %v", err)
+		}
+		keys = append(keys, pdfArchiveKey(pdfID, causeList, pdf.hash))
+	}
+
+	for _, key := range keys {
+		if _, err := blobStore.Stat(ctx, key); err != nil {
+			t.Errorf("version %s: %v", key, err)
+		}
+	}
+	pdf, err := readArchivedPDF(ctx, pdfID, causeList)
+	if err != nil {
+		t.Fatalf("readArchivedPDF: %v", err)
+	}
+	if string(pdf.data) != "corrected upload" {
+		t.Errorf("read %q, want the version archived last", pdf.data)
+	}
+}
@@ -1,248 +1,566 @@
-package main
-
-import (
-	"bytes"
-	"fmt" // For formatted I/O like Printf, Sprintf, etc.
-	"io/ioutil"
-
-	// For string manipulations like splitting, trimming, etc.\
-	"log"
-	"net/http" // For extracting text from PDF pages
-	"regexp"
-	"strings"
-
-	"github.com/unidoc/unipdf/v3/extractor"
-	"github.com/unidoc/unipdf/v3/model"
-)
-
-type CauseListEntry struct {
-	Sno       string // Serial number
-	CaseNo    string // Case number
-	DiaryNo   string // Diary number
-	CaseNoMap string // Mapped case number with proper formatting
-	JudgeName string // Name of the judge
-	CourtNo   string // Court number
-}
-
-func caseNumberCleaner(caseNum string) (string, string, string, string) {
-	// Check if "in " is present in the case number
-	if strings.Contains(caseNum, "in ") {
-		return "", "", "", ""
-	}
-
-	// Handle "Connected" case
-	if strings.Contains(caseNum, "Connected") {
-		tempSno := strings.ReplaceAll(strings.Split(caseNum, "Connected")[0], " ", "")
-		tempSno = strings.ReplaceAll(tempSno, "\n", "")
-		caseNum = strings.Replace(caseNum, strings.Split(caseNum, "Connected")[0], tempSno, 1)
-	}
-
-	// Clean up the case number string
-	caseNum = strings.ReplaceAll(caseNum, "\n", " ")
-	caseNum = strings.ReplaceAll(caseNum, "Connected", "")
-	caseNum = strings.ReplaceAll(caseNum, "  ", " ")
-
-	// Split case number into serial number (sno) and the rest
-	parts := strings.SplitN(caseNum, " ", 2)
-	sno := strings.TrimSpace(parts[0])
-	caseNum = strings.TrimSpace(parts[1])
-
-	// Handle "Diary No." or "Dno"
-	if strings.Contains(caseNum, "Diary No.") || strings.Contains(caseNum, "Dno") {
-		diaryNo := strings.Split(caseNum, " ")[len(strings.Split(caseNum, " "))-1]
-		return sno, "", "", diaryNo
-	}
-
-	// Handle case numbers with "./" or "/"
-	var caseNumRep string
-	if strings.Contains(caseNum, "./") {
-		caseNumRep = strings.Split(caseNum, "./")[1]
-	} else {
-		caseNumRep = strings.Split(caseNum, "/")[0]
-	}
-	caseNumRep = strings.TrimSpace(strings.Split(caseNumRep, " ")[len(strings.Split(caseNumRep, " "))-1])
-
-	// Handle numbers with "-" or without "-"
-	var number string
-	if strings.Contains(caseNumRep, "-") {
-		numberParts := strings.Split(caseNumRep, "-")
-		// Pad the first part with leading zeros if it's less than 6 characters
-		if len(numberParts[0]) < 6 {
-			numberParts[0] = fmt.Sprintf("%06s", numberParts[0])
-		}
-		// Pad the second part with leading zeros if it's present and less than 6 characters
-		if len(numberParts) > 1 && len(numberParts[1]) > 0 {
-			numberParts[1] = fmt.Sprintf("%06s", numberParts[1])
-			number = fmt.Sprintf("%s - %s", numberParts[0], numberParts[1])
-		}
-	} else {
-		// If there's no "-", pad the number and duplicate it with a dash
-		if len(caseNumRep) < 6 {
-			number = fmt.Sprintf("%06s - %06s", caseNumRep, caseNumRep)
-		} else {
-			number = caseNumRep + " - " + caseNumRep
-		}
-	}
-
-	// Replace the original case number with the formatted version
-	caseNumMap := strings.Replace(caseNum, caseNumRep, number, 1)
-
-	return sno, caseNum, caseNumMap, ""
-}
-
-func getCaseNumber(data string) []string {
-	// Define the regular expression pattern
-	pattern := `\d{1,}[.]?(\d{1,})?\s(\d{1,})?(Connected\s)?[a-zA-Z]+.[a-zA-Z]+..?([a-zA-Z]+)?.?.?\sNo.\s\d{1,}-?(\d{1,})?\/\d{4}\b|` +
-		`\d{1,}[.]?(\d{1,})?\s?(Connected)?\s?MA\s\d{1,}-?(\d{1,})?\/\d{4}|` +
-		`\d{1,}[.]?(\d{1,})?\s?(\d{1,})?(Connected)?\s?Diary\sNo.\s\d{1,}-?\/?\d{4}|` +
-		`\d{1,}[.]?(\d{1,})?\s(\d{1,})?[a-zA-Z]+.[a-zA-Z]+..?([a-zA-Z]+)?.?.?\sNo.\s?.?\d{1,}-?(\d{1,})?\/\d{4}|` +
-		`\d{1,}[.]?(\d{1,})?\s?(\d{1,})?(Connected)?\s?Dno\s\d{1,}-?\/?\d{4}`
-
-	// Compile the regular expression
-	re := regexp.MustCompile(pattern)
-
-	// Find all matches in the input string
-	matches := re.FindAllString(data, -1)
-
-	// Return the list of matched strings
-	return matches
-}
-
-func getCourtNoAndJudge(text string) (string, string) {
-	findText := "court no. :"
-	if !strings.Contains(strings.ToLower(text), "court no. :") {
-		findText = "dated :"
-	}
-	if strings.Contains(strings.ToLower(text), "registrar court no.") {
-		findText = "registrar court no."
-	}
-
-	lines := strings.Split(text, "\n")
-	var judgeCheck bool
-	var courtNo string
-	var judges []string
-
-	for _, line := range lines {
-		lineLower := strings.ToLower(line)
-		if strings.Contains(lineLower, "(time :") || strings.Contains(lineLower, "note:") || strings.Contains(lineLower, "this bench") {
-			break
-		}
-		if judgeCheck {
-			judges = append(judges, strings.TrimSpace(line))
-		}
-		if strings.Contains(lineLower, findText) {
-			if strings.Contains(lineLower, "court no. :") {
-				courtNo = strings.TrimSpace(strings.Split(lineLower, "court no. :")[1])
-			} else if strings.Contains(lineLower, "registrar court no.") {
-				courtNo = strings.TrimSpace(strings.Split(lineLower, "registrar court no.")[1])
-			}
-			judgeCheck = true
-		}
-	}
-
-	return courtNo, strings.Join(judges, ", ")
-}
-
-func extractTextFromPDFURL(pdfURL string) (string, error) {
-	// Download the PDF file
-	resp, err := http.Get(pdfURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to download PDF: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read the response body into a byte slice
-	pdfData, err := ioutil.ReadAll(resp.Body) // Read the entire response
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Create a PDF reader from the buffer
-	pdfReader, err := model.NewPdfReader(bytes.NewReader(pdfData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create PDF reader: %w", err)
-	}
-
-	var text strings.Builder // Use strings.Builder for efficient string concatenation
-
-	// Get the number of pages in the PDF
-	numPages, err := pdfReader.GetNumPages()
-	if err != nil {
-		return "", fmt.Errorf("failed to get number of pages: %w", err)
-	}
-
-	// Iterate through the pages and extract text
-	for i := 1; i <= numPages; i++ {
-		page, err := pdfReader.GetPage(i)
-		if err != nil {
-			return "", fmt.Errorf("failed to get page %d: %w", i, err)
-		}
-
-		// Create a text extractor for the page
-		textExtractor, err := extractor.New(page) // Capture both returned values
-		if err != nil {
-			return "", fmt.Errorf("failed to create text extractor for page %d: %w", i, err)
-		}
-
-		// Extract text from the page
-		pageText, err := textExtractor.ExtractText()
-		if err != nil {
-			return "", fmt.Errorf("failed to extract text from page %d: %w", i, err)
-		}
-		text.WriteString(pageText) // Append the text using strings.Builder // Append the text using strings.Builder
-	}
-
-	return text.String(), nil // Return the concatenated text
-}
-
-// Function to parse and extract details from PDF data
-func parseCauselistPDFData(data map[string]CauseList) []CauseListEntry {
-	var causelists []CauseListEntry
-  
-	for pdfID, entry := range data {
-		// Get the PDF link, date of hearing, and description from the CauseList struct
-		log.Println(pdfID)
-		pdfLink := entry.PDFLink
-		// Extract text from the PDF
-		pdfText, err := extractTextFromPDFURL(pdfLink)
-		if err != nil {
-			fmt.Printf("Failed to extract text from %s: %v\n", pdfLink, err)
-			continue
-		}
-
-		// Split text by a common identifier
-		if strings.Contains(pdfText, "SUPREME COURT OF INDIA") {
-			textSections := strings.Split(pdfText, "SUPREME COURT OF INDIA")
-
-			for _, eachText := range textSections {
-				if !strings.Contains(eachText, "No.") {
-					continue
-				}
-
-				// Extract court number and judge names
-				courtNo, judges := getCourtNoAndJudge(eachText)
-
-				// Clean and extract case numbers
-				caseNos := getCaseNumber(eachText)
-				for _, caseNo := range caseNos {
-					// Clean the case number and other details
-					sno, cleanCaseNo, caseNoMap, diaryNo := caseNumberCleaner(caseNo)
-
-					// Create the CauseListEntry struct
-					causelist := CauseListEntry{
-						Sno:       sno,
-						CaseNo:    cleanCaseNo,
-						DiaryNo:   diaryNo,
-						CaseNoMap: caseNoMap,
-						JudgeName: judges,
-						CourtNo:   courtNo,
-					}
-
-					// Append the cause list entry to the result slice
-					causelists = append(causelists, causelist)
-				}
-			}
-		}
-	}
-	return causelists
-}
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt" // For formatted I/O like Printf, Sprintf, etc.
+
+	// For string manipulations like splitting, trimming, etc.\
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unidoc/unipdf/v3/extractor"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+type CauseListEntry struct {
+	Sno                 string   // Item number, e.g. "12", or "12.1" for a connected matter
+	CaseNo              string   // Case number
+	DiaryNo             string   // Diary number
+	CaseNoMap           string   // Mapped case number with proper formatting
+	Judges              []string // Bench composition, presiding judge first
+	CourtNo             string   // Court number
+	Section             string   // List section heading, e.g. "FRESH MATTERS"
+	Petitioner          string   // Petitioner / appellant party name
+	Respondent          string   // Respondent party name
+	PetitionerAdvocates []string // Advocates for the petitioner
+	RespondentAdvocates []string // Advocates for the respondent
+	IANumbers           []string // Interlocutory applications listed with the matter
+	ConnectedTo         string   // Item number of the main matter, for a connected matter
+	PDFID               string   // Key of the source PDF in the cause lists of a run (see trimPDFLink)
+}
+
+// JudgeName returns the bench as one comma separated string
+func (e CauseListEntry) JudgeName() string {
+	return strings.Join(e.Judges, ", ")
+}
+
+func caseNumberCleaner(caseNum string) (string, string, string, string) {
+	// Check if "in " is present in the case number
+	if strings.Contains(caseNum, "in ") {
+		return "", "", "", ""
+	}
+
+	// Handle "Connected" case
+	if strings.Contains(caseNum, "Connected") {
+		tempSno := strings.ReplaceAll(strings.Split(caseNum, "Connected")[0], " ", "")
+		tempSno = strings.ReplaceAll(tempSno, "\n", "")
+		caseNum = strings.Replace(caseNum, strings.Split(caseNum, "Connected")[0], tempSno, 1)
+	}
+
+	// Clean up the case number string
+	caseNum = strings.ReplaceAll(caseNum, "\n", " ")
+	caseNum = strings.ReplaceAll(caseNum, "Connected", "")
+	caseNum = strings.ReplaceAll(caseNum, "  ", " ")
+
+	// Split case number into serial number (sno) and the rest
+	parts := strings.SplitN(caseNum, " ", 2)
+	sno := strings.TrimSpace(parts[0])
+	caseNum = strings.TrimSpace(parts[1])
+
+	// Handle "Diary No." or "Dno"
+	if strings.Contains(caseNum, "Diary No.") || strings.Contains(caseNum, "Dno") {
+		diaryNo := strings.Split(caseNum, " ")[len(strings.Split(caseNum, " "))-1]
+		return sno, "", "", diaryNo
+	}
+
+	// Handle case numbers with "./" or "/"
+	var caseNumRep string
+	if strings.Contains(caseNum, "./") {
+		caseNumRep = strings.Split(caseNum, "./")[1]
+	} else {
+		caseNumRep = strings.Split(caseNum, "/")[0]
+	}
+	caseNumRep = strings.TrimSpace(strings.Split(caseNumRep, " ")[len(strings.Split(caseNumRep, " "))-1])
+
+	// Handle numbers with "-" or without "-"
+	var number string
+	if strings.Contains(caseNumRep, "-") {
+		numberParts := strings.Split(caseNumRep, "-")
+		// Pad the first part with leading zeros if it's less than 6 characters
+		if len(numberParts[0]) < 6 {
+			numberParts[0] = fmt.Sprintf("%06s", numberParts[0])
+		}
+		// Pad the second part with leading zeros if it's present and less than 6 characters
+		if len(numberParts) > 1 && len(numberParts[1]) > 0 {
+			numberParts[1] = fmt.Sprintf("%06s", numberParts[1])
+			number = fmt.Sprintf("%s - %s", numberParts[0], numberParts[1])
+		}
+	} else {
+		// If there's no "-", pad the number and duplicate it with a dash
+		if len(caseNumRep) < 6 {
+			number = fmt.Sprintf("%06s - %06s", caseNumRep, caseNumRep)
+		} else {
+			number = caseNumRep + " - " + caseNumRep
+		}
+	}
+
+	// Replace the original case number with the formatted version
+	caseNumMap := strings.Replace(caseNum, caseNumRep, number, 1)
+
+	return sno, caseNum, caseNumMap, ""
+}
+
+// getCaseNumberIndexes returns the start and end offsets of every case number in data
+func getCaseNumberIndexes(data string) [][]int {
+	return caseNumberRegexp.FindAllStringIndex(data, -1)
+}
+
+// caseNumberRegexp matches the item number and case or diary number of an item
+var caseNumberRegexp = compileCaseNumberPattern()
+
+func compileCaseNumberPattern() *regexp.Regexp {
+	// Define the regular expression pattern
+	pattern := `\d{1,}[.]?(\d{1,})?\s(\d{1,})?(Connected\s)?[a-zA-Z]+.[a-zA-Z]+..?([a-zA-Z]+)?.?.?\sNo.\s\d{1,}-?(\d{1,})?\/\d{4}\b|` +
+		`\d{1,}[.]?(\d{1,})?\s?(Connected)?\s?MA\s\d{1,}-?(\d{1,})?\/\d{4}|` +
+		`\d{1,}[.]?(\d{1,})?\s?(\d{1,})?(Connected)?\s?Diary\sNo.\s\d{1,}-?\/?\d{4}|` +
+		`\d{1,}[.]?(\d{1,})?\s(\d{1,})?[a-zA-Z]+.[a-zA-Z]+..?([a-zA-Z]+)?.?.?\sNo.\s?.?\d{1,}-?(\d{1,})?\/\d{4}|` +
+		`\d{1,}[.]?(\d{1,})?\s?(\d{1,})?(Connected)?\s?Dno\s\d{1,}-?\/?\d{4}`
+
+	// Compile the regular expression
+	return regexp.MustCompile(pattern)
+}
+
+func getCourtNoAndJudge(text string) (string, []string) {
+	findText := "court no. :"
+	if !strings.Contains(strings.ToLower(text), "court no. :") {
+		findText = "dated :"
+	}
+	if strings.Contains(strings.ToLower(text), "registrar court no.") {
+		findText = "registrar court no."
+	}
+
+	lines := strings.Split(text, "\n")
+	var judgeCheck bool
+	var courtNo string
+	var judges []string
+
+	for _, line := range lines {
+		lineLower := strings.ToLower(line)
+		if strings.Contains(lineLower, "(time :") || strings.Contains(lineLower, "note:") || strings.Contains(lineLower, "this bench") {
+			break
+		}
+		if judgeCheck && strings.TrimSpace(line) != "" {
+			judges = append(judges, strings.TrimSpace(line))
+		}
+		if strings.Contains(lineLower, findText) {
+			if strings.Contains(lineLower, "court no. :") {
+				courtNo = strings.TrimSpace(strings.Split(lineLower, "court no. :")[1])
+			} else if strings.Contains(lineLower, "registrar court no.") {
+				courtNo = strings.TrimSpace(strings.Split(lineLower, "registrar court no.")[1])
+			}
+			judgeCheck = true
+		}
+	}
+
+	return courtNo, judges
+}
+
+// sectionHeadingPattern matches a list section heading line such as
+// "FRESH MATTERS", "AFTER NOTICE" or "FOR JUDGMENT"
+var sectionHeadingPattern = regexp.MustCompile(`(?m)^[ \t]*((?:[A-Z][A-Z()&/-]*[ \t]+)*(?:MATTERS?|NOTICE|JUDG(?:E)?MENTS?|HEARING|ORDERS|ADMISSION|APPLICATIONS?|CASES)(?:[ \t]+[A-Z()&/-]+)*)[ \t]*$`)
+
+// sectionAt returns the last section heading of text before offset pos
+func sectionAt(headings [][]int, text string, pos int) string {
+	var section string
+	for _, h := range headings {
+		if h[0] > pos {
+			break
+		}
+		section = strings.Join(strings.Fields(text[h[2]:h[3]]), " ")
+	}
+	return section
+}
+
+// iaNumberPattern matches an interlocutory application number, e.g. "IA No. 12345/2024"
+var iaNumberPattern = regexp.MustCompile(`I\.?A\.?\s*No\.?\s*(\d+/\d{4})`)
+
+// versusPattern splits the petitioner and respondent halves of an item
+var versusPattern = regexp.MustCompile(`(?im)^\s*(?:versus|vs\.?)\s*$`)
+
+// columnGap separates the party column from the advocate column
+var columnGap = regexp.MustCompile(`\s{2,}`)
+
+// sectionCodePattern matches the registry section printed under a case number, e.g. "IV-A" or "XVI"
+var sectionCodePattern = regexp.MustCompile(`^(?:SECTION\s+)?[IVXPL]+(?:-[A-Z]+)?$`)
+
+// itemDetails are the parties, advocates and applications of one item
+type itemDetails struct {
+	Petitioner          string
+	Respondent          string
+	PetitionerAdvocates []string
+	RespondentAdvocates []string
+	IANumbers           []string
+}
+
+// parseItemDetails reads the text of one item, from after its case number up
+// to the next case number. The party name is in the left column and the
+// advocates in the right one, with "Versus" between the two sides
+func parseItemDetails(block string) itemDetails {
+	var details itemDetails
+	for _, m := range iaNumberPattern.FindAllStringSubmatch(block, -1) {
+		details.IANumbers = append(details.IANumbers, m[1])
+	}
+
+	sides := versusPattern.Split(block, 2)
+	details.Petitioner, details.PetitionerAdvocates = parsePartyColumns(sides[0])
+	if len(sides) > 1 {
+		details.Respondent, details.RespondentAdvocates = parsePartyColumns(sides[1])
+	}
+	return details
+}
+
+// parsePartyColumns splits one side of an item into the party name and its advocates
+func parsePartyColumns(text string) (string, []string) {
+	var party []string
+	var advocates []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || iaNumberPattern.MatchString(line) || sectionCodePattern.MatchString(line) ||
+			sectionHeadingPattern.MatchString(line) {
+			continue
+		}
+		columns := columnGap.Split(line, -1)
+		if len(columns) > 1 {
+			advocates = append(advocates, splitAdvocates(columns[len(columns)-1])...)
+			line = strings.Join(columns[:len(columns)-1], " ")
+		}
+		if !sectionCodePattern.MatchString(line) {
+			party = append(party, line)
+		}
+	}
+	return strings.Join(party, " "), advocates
+}
+
+// splitAdvocates splits an advocate column listing several advocates
+func splitAdvocates(column string) []string {
+	var advocates []string
+	for _, name := range strings.Split(column, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			advocates = append(advocates, name)
+		}
+	}
+	return advocates
+}
+
+// mainItemOf returns the item number of the main matter of a connected item
+// ("12.1" gives "12"), or "" for a main item
+func mainItemOf(sno string, connected bool) string {
+	if i := strings.Index(sno, "."); i > 0 {
+		return sno[:i]
+	}
+	if connected {
+		return sno
+	}
+	return ""
+}
+
+// downloadedPDF is a cause list PDF as downloaded
+type downloadedPDF struct {
+	data      []byte
+	hash      string // Hex SHA-256 of data
+	fetchedAt time.Time
+}
+
+// downloadPDF downloads a PDF and hashes its content
+func downloadPDF(ctx context.Context, pdfURL string) (*downloadedPDF, error) {
+	resp, err := fetcher.Get(ctx, pdfURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download PDF: %w", err)
+	}
+	sum := sha256.Sum256(resp.Body)
+	return &downloadedPDF{data: resp.Body, hash: hex.EncodeToString(sum[:]), fetchedAt: time.Now()}, nil
+}
+
+func extractTextFromPDF(pdfData []byte) (string, error) {
+	// Create a PDF reader from the buffer
+	pdfReader, err := model.NewPdfReader(bytes.NewReader(pdfData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create PDF reader: %w", err)
+	}
+
+	var text strings.Builder // Use strings.Builder for efficient string concatenation
+
+	// Get the number of pages in the PDF
+	numPages, err := pdfReader.GetNumPages()
+	if err != nil {
+		return "", fmt.Errorf("failed to get number of pages: %w", err)
+	}
+
+	// Iterate through the pages and extract text
+	for i := 1; i <= numPages; i++ {
+		page, err := pdfReader.GetPage(i)
+		if err != nil {
+			return "", fmt.Errorf("failed to get page %d: %w", i, err)
+		}
+
+		// Create a text extractor for the page
+		textExtractor, err := extractor.New(page) // Capture both returned values
+		if err != nil {
+			return "", fmt.Errorf("failed to create text extractor for page %d: %w", i, err)
+		}
+
+		// Extract text from the page
+		pageText, err := textExtractor.ExtractText()
+		if err != nil {
+			return "", fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+		text.WriteString(pageText) // Append the text using strings.Builder // Append the text using strings.Builder
+	}
+
+	return text.String(), nil // Return the concatenated text
+}
+
+// pdfJob is one cause list PDF for the worker pool of parseCauselistPDFData
+type pdfJob struct {
+	pdfID     string
+	causeList CauseList
+	pdf       *downloadedPDF // Already downloaded, e.g. by a daemon recheck; nil to load it
+}
+
+// pdfResult is the outcome of a pdfJob
+type pdfResult struct {
+	pdfID      string
+	entries    []CauseListEntry
+	version    PDFVersion
+	err        error
+	archiveErr error // Set when the PDF parsed but could not be archived
+}
+
+// Function to parse and extract details from PDF data. The PDFs are downloaded
+// and extracted by appConfig.Scraper.Concurrency workers, rate limited per
+// host. Entries from the PDFs that could be read are always returned, sorted
+// by hearing date, list type and item number, with the content hash of each
+// PDF by PDF ID. Every PDF is recorded in runSummary; the ones that failed,
+// or parsed but were not archived, are reported in a *PDFErrors. The PDFs in
+// fetched are parsed as they are instead of being loaded again. Cancelling
+// ctx stops the workers
+func parseCauselistPDFData(ctx context.Context, data map[string]CauseList, fetched map[string]*downloadedPDF) ([]CauseListEntry, map[string]PDFVersion, error) {
+	workers := appConfig.Scraper.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	limiter := newHostRateLimiter(appConfig.Scraper.RateLimit)
+
+	jobs := make(chan pdfJob)
+	results := make(chan pdfResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- parseCauselistPDF(ctx, limiter, job)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for pdfID, causeList := range data {
+			select {
+			case jobs <- pdfJob{pdfID: pdfID, causeList: causeList, pdf: fetched[pdfID]}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var causelists []CauseListEntry
+	versions := make(map[string]PDFVersion)
+	failures := &PDFErrors{Total: len(data), Errors: make(map[string]error)}
+	for result := range results {
+		if result.err != nil {
+			failures.Errors[result.pdfID] = result.err
+			runSummary.RecordPDF(PDFOutcome{PDFID: result.pdfID, Err: result.err})
+			continue
+		}
+		if result.archiveErr != nil {
+			failures.Errors[result.pdfID] = result.archiveErr
+		}
+		runSummary.RecordPDF(PDFOutcome{PDFID: result.pdfID, Parsed: true, Entries: len(result.entries), Err: result.archiveErr})
+		causelists = append(causelists, result.entries...)
+		versions[result.pdfID] = result.version
+	}
+	sortCauseListEntries(causelists, data)
+
+	if err := ctx.Err(); err != nil {
+		return causelists, versions, fmt.Errorf("parsing PDFs interrupted: %w", err)
+	}
+	if len(failures.Errors) > 0 {
+		return causelists, versions, failures
+	}
+	return causelists, versions, nil
+}
+
+// parseCauselistPDF loads one cause list PDF and parses it with its court
+func parseCauselistPDF(ctx context.Context, limiter *hostRateLimiter, job pdfJob) pdfResult {
+	pdfLink := job.causeList.PDFLink
+	log.Printf("Parsing %s (%s)", job.pdfID, pdfLink)
+	court, err := courtByID(job.causeList.CourtID())
+	if err != nil {
+		return pdfResult{pdfID: job.pdfID, err: &ParseError{Source: job.pdfID, Err: err}}
+	}
+	pdf, err := loadCauselistPDF(ctx, limiter, court, job)
+	if err != nil {
+		fmt.Printf("Failed to load %s: %v\n", pdfLink, err)
+		return pdfResult{pdfID: job.pdfID, err: err}
+	}
+	// Archive before extracting so a PDF the parser chokes on can be reparsed later
+	var archiveErr error
+	if appConfig.Scraper.PDFSource != "archive" && appConfig.Storage.ArchivePDFs {
+		if archiveErr = archivePDF(ctx, job.pdfID, job.causeList, pdf); archiveErr != nil {
+			log.Printf("Failed to archive %s: %v", pdfLink, archiveErr)
+		}
+	}
+	parsed, err := court.ParseList(job.pdfID, pdf.data)
+	if err != nil {
+		fmt.Printf("Failed to parse %s: %v\n", pdfLink, err)
+		return pdfResult{pdfID: job.pdfID, err: &ParseError{Source: job.pdfID, Err: err}}
+	}
+	entries := parsed.Entries
+	checkHeaderDate(job.pdfID, job.causeList, parsed.HearingDate)
+	return pdfResult{
+		pdfID:      job.pdfID,
+		entries:    entries,
+		version:    PDFVersion{Hash: pdf.hash, FetchedAt: pdf.fetchedAt, Entries: len(entries)},
+		archiveErr: archiveErr,
+	}
+}
+
+// loadCauselistPDF fetches the PDF of a job from its court, or reads it from
+// the blob store with scraper.pdf_source "archive" so reparsing does not touch
+// the court site. Errors are a *FetchError or a *StorageError
+func loadCauselistPDF(ctx context.Context, limiter *hostRateLimiter, court Court, job pdfJob) (*downloadedPDF, error) {
+	if job.pdf != nil {
+		return job.pdf, nil
+	}
+	if appConfig.Scraper.PDFSource == "archive" {
+		return readArchivedPDF(ctx, job.pdfID, job.causeList)
+	}
+	if err := limiter.Wait(ctx, job.causeList.PDFLink); err != nil {
+		return nil, &FetchError{URL: job.causeList.PDFLink, Err: err}
+	}
+	pdf, err := court.FetchList(ctx, job.pdfID, job.causeList)
+	if err != nil {
+		return nil, &FetchError{URL: job.causeList.PDFLink, Err: err}
+	}
+	return pdf, nil
+}
+
+// parseCauselistPDFText extracts the entries of the text of one Supreme Court
+// cause list PDF. Every page starts with the court's name
+func parseCauselistPDFText(pdfID, pdfText string) []CauseListEntry {
+	var entries []CauseListEntry
+
+	// Split text by a common identifier
+	if strings.Contains(pdfText, "SUPREME COURT OF INDIA") {
+		textSections := strings.Split(pdfText, "SUPREME COURT OF INDIA")
+
+		for _, eachText := range textSections {
+			if !strings.Contains(eachText, "No.") {
+				continue
+			}
+
+			// Extract court number and judge names
+			courtNo, judges := getCourtNoAndJudge(eachText)
+
+			// Section headings apply to every item after them
+			headings := sectionHeadingPattern.FindAllStringSubmatchIndex(eachText, -1)
+
+			// Clean and extract case numbers; the text up to the next case
+			// number holds the parties, advocates and applications of the item
+			caseNoIndexes := getCaseNumberIndexes(eachText)
+			lastMain := ""
+			for i, loc := range caseNoIndexes {
+				caseNo := eachText[loc[0]:loc[1]]
+				blockEnd := len(eachText)
+				if i+1 < len(caseNoIndexes) {
+					blockEnd = caseNoIndexes[i+1][0]
+				}
+				details := parseItemDetails(eachText[loc[1]:blockEnd])
+
+				// Clean the case number and other details
+				sno, cleanCaseNo, caseNoMap, diaryNo := caseNumberCleaner(caseNo)
+
+				connectedTo := mainItemOf(sno, strings.Contains(caseNo, "Connected"))
+				if connectedTo == "" {
+					lastMain = sno
+				} else if sno == connectedTo {
+					// A "Connected" item without a sub-number belongs to the item above it
+					connectedTo = lastMain
+				}
+
+				// Create the CauseListEntry struct
+				causelist := CauseListEntry{
+					Sno:                 sno,
+					CaseNo:              cleanCaseNo,
+					DiaryNo:             diaryNo,
+					CaseNoMap:           caseNoMap,
+					Judges:              judges,
+					CourtNo:             courtNo,
+					Section:             sectionAt(headings, eachText, loc[0]),
+					Petitioner:          details.Petitioner,
+					Respondent:          details.Respondent,
+					PetitionerAdvocates: details.PetitionerAdvocates,
+					RespondentAdvocates: details.RespondentAdvocates,
+					IANumbers:           details.IANumbers,
+					ConnectedTo:         connectedTo,
+					PDFID:               pdfID,
+				}
+
+				// Append the cause list entry to the result slice
+				entries = append(entries, causelist)
+			}
+		}
+	}
+	return entries
+}
+
+// sortCauseListEntries orders entries by hearing date, list type and item
+// number, so the result does not depend on which PDF finished first
+func sortCauseListEntries(entries []CauseListEntry, data map[string]CauseList) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := data[entries[i].PDFID], data[entries[j].PDFID]
+		if !a.DateOfHearing.Equal(b.DateOfHearing) {
+			return a.DateOfHearing.Before(b.DateOfHearing.Time)
+		}
+		if a.Description != b.Description {
+			return a.Description < b.Description
+		}
+		if c := compareItemNumbers(entries[i].Sno, entries[j].Sno); c != 0 {
+			return c < 0
+		}
+		return entries[i].PDFID < entries[j].PDFID
+	})
+}
+
+// compareItemNumbers compares item numbers such as "2", "12" and "12.1" numerically
+func compareItemNumbers(a, b string) int {
+	ap, bp := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, aerr := strconv.Atoi(ap[i])
+		bn, berr := strconv.Atoi(bp[i])
+		switch {
+		case aerr == nil && berr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case (aerr != nil || berr != nil) && ap[i] != bp[i]:
+			return strings.Compare(ap[i], bp[i])
+		}
+	}
+	return len(ap) - len(bp)
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// CauseListQuery selects the cause lists the search form is submitted for.
+// List type, court and bench are matched against the options of the form, so
+// they can be given the way the site labels them
+type CauseListQuery struct {
+	Date     time.Time
+	ListType string // e.g. "Misc", "Regular", "Chamber" or "Registrar"
+	Court    string // Court number, e.g. "2"
+	Bench    string // Part of a judge's name
+}
+
+// errSearchRejected is returned when the site refuses a search, usually
+// because its nonce/CSRF token has expired
+var errSearchRejected = errors.New("search rejected")
+
+// searchForm is the cause list search form found on the cause list page
+type searchForm struct {
+	action  string
+	method  string
+	values  url.Values              // Every field with its default value, hidden tokens included
+	names   []string                // Field names in document order
+	selects map[string][]formOption // Options of each <select>
+	radios  map[string][]string     // Values of each radio group
+}
+
+type formOption struct {
+	value string
+	label string
+}
+
+// searchCauselistPage submits the search form of the cause list page at
+// pageURL and returns the HTML of the results and the URL its links are
+// relative to. The form is loaded fresh for every search so its tokens are
+// current; a rejected search is tried once more. Cancelling ctx stops the search
+func searchCauselistPage(ctx context.Context, pageURL string, query CauseListQuery) ([]byte, string, error) {
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		page, err := fetchCauselistPage(ctx, pageURL)
+		if err != nil {
+			return nil, "", err
+		}
+		form, err := parseSearchForm(page.Body, page.URL)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := form.fill(query); err != nil {
+			return nil, "", err
+		}
+		if appConfig.Scraper.SearchURL != "" {
+			form.action = appConfig.Scraper.SearchURL
+		}
+
+		resp, err := sciSession.Submit(ctx, form.method, form.action, form.values)
+		if err != nil {
+			return nil, "", err
+		}
+		results, err := searchResults(resp.Body)
+		if !errors.Is(err, errSearchRejected) {
+			// The HTML of an AJAX search is shown on the form's page
+			resultsURL := resp.URL
+			if isJSON(resp.Body) {
+				resultsURL = page.URL
+			}
+			return results, resultsURL, err
+		}
+		log.Printf("Cause list search rejected, reloading the form: %v", err)
+		lastErr = err
+	}
+	return nil, "", lastErr
+}
+
+// parseSearchForm finds the first form of the page that has a date field and
+// reads its fields, their defaults and the options of its selects and radios
+func parseSearchForm(page []byte, pageURL string) (*searchForm, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page URL: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", pageURL, err)
+	}
+
+	var form *searchForm
+	doc.Find("form").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		form = newSearchForm(base, s)
+		return !form.hasDateField()
+	})
+	if form == nil || !form.hasDateField() {
+		return nil, fmt.Errorf("no cause list search form with a date field on %s", pageURL)
+	}
+	return form, nil
+}
+
+// newSearchForm reads the fields of a form, in document order
+func newSearchForm(base *url.URL, s *goquery.Selection) *searchForm {
+	action, _ := url.Parse(s.AttrOr("action", ""))
+	form := &searchForm{
+		action:  base.ResolveReference(action).String(),
+		method:  strings.ToUpper(s.AttrOr("method", "")),
+		values:  url.Values{},
+		selects: make(map[string][]formOption),
+		radios:  make(map[string][]string),
+	}
+	if form.method == "" {
+		form.method = http.MethodGet
+	}
+
+	s.Find("input, select").Each(func(_ int, field *goquery.Selection) {
+		name := field.AttrOr("name", "")
+		if name == "" {
+			return
+		}
+		form.addName(name)
+		if goquery.NodeName(field) == "select" {
+			form.addSelect(name, field)
+			return
+		}
+
+		value := field.AttrOr("value", "")
+		_, checked := field.Attr("checked")
+		switch strings.ToLower(field.AttrOr("type", "")) {
+		case "radio":
+			form.radios[name] = append(form.radios[name], value)
+			if checked || form.values.Get(name) == "" {
+				form.values.Set(name, value)
+			}
+		case "checkbox":
+			if checked {
+				form.values.Add(name, value)
+			}
+		case "button", "image", "reset", "file":
+		default:
+			form.values.Set(name, value)
+		}
+	})
+	return form
+}
+
+// addSelect records the options of a select; the selected one, or else the
+// first, is its default
+func (f *searchForm) addSelect(name string, field *goquery.Selection) {
+	field.Find("option").Each(func(_ int, o *goquery.Selection) {
+		option := formOption{label: strings.TrimSpace(o.Text())}
+		var hasValue bool
+		if option.value, hasValue = o.Attr("value"); !hasValue {
+			// An option without a value attribute submits its label
+			option.value = option.label
+		}
+		f.selects[name] = append(f.selects[name], option)
+		if _, selected := o.Attr("selected"); selected || !f.values.Has(name) {
+			f.values.Set(name, option.value)
+		}
+	})
+}
+
+// hasDateField tells whether the form has a listing date field
+func (f *searchForm) hasDateField() bool {
+	return f.field("date") != ""
+}
+
+// addName records a field name the first time it is seen
+func (f *searchForm) addName(name string) {
+	if !slices.Contains(f.names, name) {
+		f.names = append(f.names, name)
+	}
+}
+
+// field returns the name of a field with word in its name, e.g. "listing_date" for "date"
+func (f *searchForm) field(word string) string {
+	return f.findName(f.values.Has, word)
+}
+
+// selectField returns the name of a select with one of words in its name
+func (f *searchForm) selectField(words ...string) string {
+	return f.findName(func(name string) bool { return f.selects[name] != nil }, words...)
+}
+
+// findName returns the field named exactly one of words or, failing that, the
+// first with one of words in its name, in document order so the same field is
+// picked on every run, e.g. "from_date" over "to_date". Only the names keep
+// accepts are considered
+func (f *searchForm) findName(keep func(name string) bool, words ...string) string {
+	for _, exact := range []bool{true, false} {
+		for _, name := range f.names {
+			if !keep(name) {
+				continue
+			}
+			for _, word := range words {
+				if (exact && strings.EqualFold(name, word)) || (!exact && nameHasWord(name, word)) {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// nameHasWord tells whether a field name such as "causelist_type" or "courtNo"
+// has a word starting with word; "update_nonce" has no word "date"
+func nameHasWord(name, word string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r < 'a' || r > 'z' })
+	for _, w := range words {
+		if strings.HasPrefix(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// fill sets the date, list type, court and bench of the query on the form.
+// The form searches by court or by judge, so a query cannot have both
+func (f *searchForm) fill(query CauseListQuery) error {
+	if query.Court != "" && query.Bench != "" {
+		return errors.New("the cause list search form searches by court or by bench, not both")
+	}
+	f.values.Set(f.field("date"), query.Date.Format(appConfig.Scraper.SearchDateLayout))
+
+	filters := []struct {
+		what  string
+		value string
+		parts []string
+		match func(option formOption, value string) bool
+	}{
+		{"list type", query.ListType, []string{"type"}, optionHasPrefix},
+		{"court", query.Court, []string{"court"}, optionIsNumber},
+		{"bench", query.Bench, []string{"judge", "bench"}, optionContains},
+	}
+	searchBy := "all"
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		name := f.selectField(filter.parts...)
+		if name == "" {
+			return fmt.Errorf("the cause list search form has no %s field", filter.what)
+		}
+		value, ok := f.matchOption(name, filter.value, filter.match)
+		if !ok {
+			return fmt.Errorf("no %s %q on the cause list search form, expected one of: %s",
+				filter.what, filter.value, strings.Join(f.optionLabels(name), ", "))
+		}
+		f.values.Set(name, value)
+		if filter.what != "list type" {
+			searchBy = filter.parts[0]
+		}
+	}
+
+	// A "search by" radio group switches between all courts, a court and a judge
+	for name, values := range f.radios {
+		for _, value := range values {
+			if strings.HasPrefix(strings.ToLower(value), searchBy) {
+				f.values.Set(name, value)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// matchOption returns the value of the first option of a select that matches
+func (f *searchForm) matchOption(name, value string, match func(formOption, string) bool) (string, bool) {
+	for _, option := range f.selects[name] {
+		if option.value != "" && match(option, strings.ToLower(strings.TrimSpace(value))) {
+			return option.value, true
+		}
+	}
+	return "", false
+}
+
+func (f *searchForm) optionLabels(name string) []string {
+	var labels []string
+	for _, option := range f.selects[name] {
+		if option.value != "" {
+			labels = append(labels, option.label)
+		}
+	}
+	return labels
+}
+
+// optionHasPrefix matches "misc" to "Miscellaneous Court"
+func optionHasPrefix(option formOption, value string) bool {
+	return strings.HasPrefix(strings.ToLower(option.label), value) || strings.EqualFold(option.value, value)
+}
+
+// optionIsNumber matches "2" to "Court No. 2" or to the value "2"
+func optionIsNumber(option formOption, value string) bool {
+	fields := strings.Fields(strings.ToLower(option.label))
+	return strings.EqualFold(option.value, value) || (len(fields) > 0 && fields[len(fields)-1] == value)
+}
+
+// optionContains matches part of a judge's name
+func optionContains(option formOption, value string) bool {
+	return strings.Contains(strings.ToLower(option.label), value)
+}
+
+// searchResults returns the HTML of a search response. The site answers AJAX
+// searches with JSON such as {"success": true, "data": {"resultsHtml": "..."}}
+// and plain searches with HTML
+func searchResults(body []byte) ([]byte, error) {
+	if !isJSON(body) {
+		return body, nil
+	}
+	trimmed := bytes.TrimSpace(body)
+
+	var resp struct {
+		Success *bool           `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(trimmed, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+	if resp.Success != nil && !*resp.Success {
+		return nil, fmt.Errorf("%w: %s", errSearchRejected, resp.Data)
+	}
+
+	var htmlData string
+	if err := json.Unmarshal(resp.Data, &htmlData); err == nil {
+		return []byte(htmlData), nil
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("unexpected search response data: %s", resp.Data)
+	}
+	for _, key := range []string{"resultsHtml", "html", "results"} {
+		if err := json.Unmarshal(data[key], &htmlData); err == nil {
+			return []byte(htmlData), nil
+		}
+	}
+	return nil, fmt.Errorf("no results HTML in search response")
+}
+
+// isJSON tells whether a response body is a JSON object
+func isJSON(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
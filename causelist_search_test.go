@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSearchPage = `<html><body>
+<form action="/login"><input name="user"></form>
+<table><tr><td>
+<form action="/wp-admin/admin-ajax.php" method="post">
+	<input type="hidden" name="scid" value="abc123">
+	<input type="text" name="listing_date" value="">
+	<input type="text" name="to_date" value="">
+	<select name="causelist_type">
+		<option value="">Select</option>
+		<option value="1">Miscellaneous Court</option>
+		<option value="2" selected>Regular Court</option>
+	</select>
+	<select name="court_no"><option value="">All</option><option value="c2">Court No. 2</option></select>
+	<select name="judge"><option>Hon'ble Mr. Justice Sanjiv Khanna</option></select>
+	<input type="radio" name="search_by" value="all_courts" checked>
+	<input type="radio" name="search_by" value="court">
+	<input type="radio" name="search_by" value="judge">
+	<input type="submit" name="go" value="Search">
+</form>
+</td></tr></table>
+</body></html>`
+
+func TestParseSearchForm(t *testing.T) {
+	form, err := parseSearchForm([]byte(testSearchPage), "https://www.sci.gov.in/cause-list/")
+	if err != nil {
+		t.Fatalf("parseSearchForm: %v", err)
+	}
+	if form.action != "https://www.sci.gov.in/wp-admin/admin-ajax.php" || form.method != http.MethodPost {
+		t.Errorf("form %s %s", form.method, form.action)
+	}
+	wantNames := []string{"scid", "listing_date", "to_date", "causelist_type", "court_no", "judge", "search_by", "go"}
+	if !slices.Equal(form.names, wantNames) {
+		t.Errorf("names %v, want %v", form.names, wantNames)
+	}
+	for name, want := range map[string]string{"scid": "abc123", "causelist_type": "2", "search_by": "all_courts", "judge": "Hon'ble Mr. Justice Sanjiv Khanna"} {
+		if got := form.values.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := form.field("date"); got != "listing_date" {
+		t.Errorf("date field %q, want listing_date", got)
+	}
+}
+
+func TestSearchFormFill(t *testing.T) {
+	appConfig.Scraper.SearchDateLayout = "02-01-2006"
+	date := time.Date(2024, 10, 16, 0, 0, 0, 0, istLocation)
+
+	form, err := parseSearchForm([]byte(testSearchPage), "https://www.sci.gov.in/cause-list/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := form.fill(CauseListQuery{Date: date, ListType: "misc", Court: "2"}); err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	for name, want := range map[string]string{"listing_date": "16-10-2024", "causelist_type": "1", "court_no": "c2", "search_by": "court"} {
+		if got := form.values.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	err = form.fill(CauseListQuery{Date: date, Court: "2", Bench: "Khanna"})
+	if err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("court and bench filled with error %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+// PDFVersion is one content version of a cause list PDF. The court sometimes
+// re-uploads a corrected PDF at the same URL; each distinct content is a version
+type PDFVersion struct {
+	Version   int       `json:"version"` // 1 for the first content seen
+	Hash      string    `json:"hash"`    // Hex SHA-256 of the PDF
+	FetchedAt time.Time `json:"fetched_at"`
+	Entries   int       `json:"entries"`
+}
+
+// Entry change kinds
+const (
+	changeAdded        = "added"
+	changeRemoved      = "removed"
+	changeMoved        = "moved"         // Item number changed
+	changeCourtChanged = "court_changed" // Court number changed
+	changeBenchChanged = "bench_changed" // Judges changed
+)
+
+// EntryChange is one difference between two versions of a cause list
+type EntryChange struct {
+	Kind        string   `json:"kind"`
+	Case        string   `json:"case"` // See entryCaseKey
+	Sno         string   `json:"sno,omitempty"`
+	PrevSno     string   `json:"prev_sno,omitempty"`
+	CourtNo     string   `json:"court_no,omitempty"`
+	PrevCourtNo string   `json:"prev_court_no,omitempty"`
+	Judges      []string `json:"judges,omitempty"`
+	PrevJudges  []string `json:"prev_judges,omitempty"`
+}
+
+// diffEntries compares the entries of two versions of a cause list, matching
+// entries on their case key. A matter listed twice is compared by its first listing
+func diffEntries(prev, next []CauseListEntry) []EntryChange {
+	prevByKey := entriesByCaseKey(prev)
+	nextByKey := entriesByCaseKey(next)
+
+	var changes []EntryChange
+	for _, entry := range next {
+		key := entryCaseKey(entry)
+		if key == "" || nextByKey[key].Sno != entry.Sno {
+			continue
+		}
+		old, ok := prevByKey[key]
+		if !ok {
+			changes = append(changes, EntryChange{Kind: changeAdded, Case: key, Sno: entry.Sno, CourtNo: entry.CourtNo, Judges: entry.Judges})
+			continue
+		}
+		if old.Sno != entry.Sno {
+			changes = append(changes, EntryChange{Kind: changeMoved, Case: key, Sno: entry.Sno, PrevSno: old.Sno})
+		}
+		if old.CourtNo != entry.CourtNo {
+			changes = append(changes, EntryChange{Kind: changeCourtChanged, Case: key, Sno: entry.Sno, CourtNo: entry.CourtNo, PrevCourtNo: old.CourtNo})
+		}
+		if !slices.Equal(old.Judges, entry.Judges) {
+			changes = append(changes, EntryChange{Kind: changeBenchChanged, Case: key, Sno: entry.Sno, Judges: entry.Judges, PrevJudges: old.Judges})
+		}
+	}
+	for _, entry := range prev {
+		key := entryCaseKey(entry)
+		if key == "" || prevByKey[key].Sno != entry.Sno {
+			continue
+		}
+		if _, ok := nextByKey[key]; !ok {
+			changes = append(changes, EntryChange{Kind: changeRemoved, Case: key, PrevSno: entry.Sno, PrevCourtNo: entry.CourtNo, PrevJudges: entry.Judges})
+		}
+	}
+	return changes
+}
+
+// entriesByCaseKey maps case keys to the first entry listing them
+func entriesByCaseKey(entries []CauseListEntry) map[string]CauseListEntry {
+	byKey := make(map[string]CauseListEntry, len(entries))
+	for _, entry := range entries {
+		if key := entryCaseKey(entry); key != "" {
+			if _, seen := byKey[key]; !seen {
+				byKey[key] = entry
+			}
+		}
+	}
+	return byKey
+}
+
+// versionUpdate is a new content version of a parsed PDF
+type versionUpdate struct {
+	pdfID   string
+	version PDFVersion
+	prev    *PDFVersion      // Latest recorded version, nil for the first one
+	stale   []CauseListEntry // Entries saved from prev, whose case index is dropped
+}
+
+// newVersions returns the parsed PDFs whose content hash changed since their
+// latest recorded version, with the entries saved from that version. It only
+// reads, so nothing is recorded until SaveParsed stores the new entries
+func newVersions(causeLists map[string]CauseList, versions map[string]PDFVersion) ([]versionUpdate, error) {
+	var updates []versionUpdate
+	for _, pdfID := range sortedKeys(causeLists) {
+		version, ok := versions[pdfID]
+		if !ok {
+			continue
+		}
+		latest, err := causeListRepo.LatestVersion(pdfID)
+		if err != nil {
+			return nil, err
+		}
+		if latest != nil && latest.Hash == version.Hash {
+			continue
+		}
+
+		update := versionUpdate{pdfID: pdfID, version: version, prev: latest}
+		update.version.Version = 1
+		if latest != nil {
+			update.version.Version = latest.Version + 1
+			update.stale, err = causeListRepo.EntriesOf(causeListDocumentID(pdfID, causeLists[pdfID]))
+			if err != nil {
+				return nil, err
+			}
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
+
+// publishVersionChanges diffs the entries of every PDF seen before against the
+// ones of its previous version and publishes a causelist.changed event. It
+// runs once the new version is saved
+func publishVersionChanges(runCtx context.Context, causeLists map[string]CauseList, entries []CauseListEntry, updates []versionUpdate) {
+	byPDF := make(map[string][]CauseListEntry)
+	for _, entry := range entries {
+		byPDF[entry.PDFID] = append(byPDF[entry.PDFID], entry)
+	}
+
+	for _, update := range updates {
+		if update.prev == nil {
+			continue
+		}
+		causeList := causeLists[update.pdfID]
+		changes := diffEntries(update.stale, byPDF[update.pdfID])
+		log.Printf("Cause list %s changed: version %d, %d entry changes", update.pdfID, update.version.Version, len(changes))
+		events.Publish(runCtx, Event{
+			Type:          EventCauseListChanged,
+			DocumentID:    causeListDocumentID(update.pdfID, causeList),
+			PDFID:         update.pdfID,
+			DateOfHearing: causeList.DateOfHearing.String(),
+			ListType:      causeList.Description,
+			PDFLink:       causeList.PDFLink,
+			Entries:       len(byPDF[update.pdfID]),
+			Version:       update.version.Version,
+			Hash:          update.version.Hash,
+			PrevHash:      update.prev.Hash,
+			Changes:       changes,
+		})
+	}
+}
+
+// pdfChanged downloads a PDF seen before and tells whether its content differs
+// from its latest recorded version. The download is returned so the changed
+// PDF is parsed from the same bytes that were hashed
+func pdfChanged(ctx context.Context, limiter *hostRateLimiter, pdfID string, causeList CauseList) (*downloadedPDF, bool, error) {
+	latest, err := causeListRepo.LatestVersion(pdfID)
+	if err != nil || latest == nil {
+		return nil, false, err
+	}
+	court, err := courtByID(causeList.CourtID())
+	if err != nil {
+		return nil, false, err
+	}
+	if err := limiter.Wait(ctx, causeList.PDFLink); err != nil {
+		return nil, false, err
+	}
+	pdf, err := court.FetchList(ctx, pdfID, causeList)
+	if err != nil {
+		return nil, false, err
+	}
+	return pdf, pdf.hash != latest.Hash, nil
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/unidoc/unipdf/v3/common/license"
+)
+
+// Exit codes returned by the command-line interface
+const (
+	exitOK           = 0
+	exitFailure      = 1 // Unexpected failure (e.g. licence setup)
+	exitUsage        = 2 // Bad command or flags
+	exitFetchFailed  = 3 // Cause list page could not be fetched
+	exitParseFailed  = 4 // Cause list or PDFs could not be parsed
+	exitSaveFailed   = 5 // Results could not be saved to Redis or CSV
+	exitNotListed    = 6 // Lookup found no listing
+	exitNotifyFailed = 7 // A watchlist notification could not be sent
+)
+
+const defaultCauselistURL = "https://www.sci.gov.in/cause-list/"
+
+// hitDateLayout is the format getSupremeCourtCauselistPDF expects in data["hitDate"],
+// and the one dates are logged in
+const hitDateLayout = "01/02/2006"
+
+const usageText = `Usage: golang-scrappers <command> [flags]
+
+Commands:
+  fetch     Fetch the cause list page and save the listed PDFs to Redis
+  parse     Fetch the cause list and parse every listed PDF
+  export    Fetch, parse and save the entries to Redis and a CSV file
+  backfill  Run export for every date in an inclusive --from/--to range,
+            resuming from checkpoints (--restart to start over)
+  lookup    Show where a case (--case) or diary number (--diary) is listed
+  watchlist Manage tracked matters ("watchlist list|add|remove")
+  serve     Serve the stored cause lists and entries as a JSON API (--addr)
+  daemon    Poll on cron schedules and parse only newly published cause lists
+  migrate   Apply pending PostgreSQL schema migrations
+  config    Print ("config print --redacted") or validate the configuration
+
+Use --court ID[,ID...] to select the courts scraped: sci (default) or dhc.
+Use --archived FILE|KEY to parse a previously archived cause list page
+from disk or the blob store instead of fetching it.
+Use --pdf-source archive to read the listed PDFs from the blob store, where
+they are archived as they are downloaded, instead of the court site.
+Use --search to submit the site's search form for the date instead of
+reading the current cause list page; --list-type, --court-no and --bench
+narrow the search and imply it; --court-no and --bench cannot be combined.
+Dates are accepted as MM/DD/YYYY, YYYY-MM-DD, "today" or "tomorrow".
+Run "golang-scrappers <command> -h" for the flags of a command.
+`
+
+// runOptions holds the flags shared by every command
+type runOptions struct {
+	date     string
+	from     string
+	to       string
+	out      string
+	archived string // Archived cause list page to parse instead of fetching
+
+	search   bool   // Submit the search form instead of reading the cause list page
+	listType string // Search filters, see CauseListQuery
+	courtNo  string
+	bench    string
+
+	restart bool // Backfill every date again, ignoring checkpoints
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches to the requested command and returns the process exit code
+func run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usageText)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "fetch", "parse", "export", "backfill":
+		return runCommand(args[0], args[1:])
+	case "config":
+		return runConfigCommand(args[1:])
+	case "migrate":
+		return runMigrate(args[1:])
+	case "lookup":
+		return runLookup(args[1:])
+	case "watchlist":
+		return runWatchlist(args[1:])
+	case "serve":
+		return runServe(args[1:])
+	case "daemon":
+		return runDaemon(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usageText)
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usageText)
+		return exitUsage
+	}
+}
+
+// runCommand parses the flags of a command and runs it for every selected date
+func runCommand(command string, args []string) int {
+	var opts runOptions
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	if command != "backfill" {
+		fs.StringVar(&opts.date, "date", "today", "hearing date")
+	}
+	fs.StringVar(&opts.from, "from", "", "first hearing date of an inclusive range")
+	fs.StringVar(&opts.to, "to", "", "last hearing date of an inclusive range")
+	if command == "export" || command == "backfill" {
+		fs.StringVar(&opts.out, "out", "causelist_data.csv", "CSV file to write entries to")
+	}
+	if command == "backfill" {
+		fs.BoolVar(&opts.restart, "restart", false, "backfill every date again instead of resuming from the checkpoints")
+	}
+	if command != "backfill" {
+		fs.StringVar(&opts.archived, "archived", "", "parse an archived cause list page (file path or blob store key) instead of fetching")
+	}
+	fs.BoolVar(&opts.search, "search", false, "submit the cause list search form for each date")
+	fs.StringVar(&opts.listType, "list-type", "", "search for one list type, e.g. Misc or Regular (implies --search)")
+	fs.StringVar(&opts.courtNo, "court-no", "", "search for the lists of one court number (implies --search)")
+	fs.StringVar(&opts.bench, "bench", "", "search for the lists of a judge, by part of the name (implies --search)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if opts.listType != "" || opts.courtNo != "" || opts.bench != "" {
+		opts.search = true
+	}
+	if opts.courtNo != "" && opts.bench != "" {
+		fmt.Fprintln(os.Stderr, "--court-no cannot be combined with --bench: the search form finds lists by court or by judge")
+		return exitUsage
+	}
+	if opts.search && opts.archived != "" {
+		fmt.Fprintln(os.Stderr, "--search cannot be combined with --archived")
+		return exitUsage
+	}
+
+	dates, err := selectDates(opts, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	c, err := cf.load()
+	if err == nil {
+		err = c.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	appConfig = c
+
+	if code := setupScraper(command); code != exitOK {
+		return code
+	}
+
+	selected, err := selectedCourts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if opts.archived != "" && len(selected) > 1 {
+		fmt.Fprintln(os.Stderr, "--archived reads the page of one court, select it with --court")
+		return exitUsage
+	}
+
+	// Backfill skips weekends, holidays and, per court, the dates an earlier run finished
+	var checkpoints CheckpointStore
+	if command == "backfill" {
+		dates = courtDays(dates, appConfig.Backfill)
+		if checkpoints, err = newCheckpointStore(appConfig.Backfill); err != nil {
+			log.Printf("Failed to set up checkpoints: %v", err)
+			return exitFailure
+		}
+	}
+
+	if err := setupLicense(); err != nil {
+		log.Printf("Failed to set metered key: %s", err)
+		return exitFailure
+	}
+
+	// Ctrl-C stops the PDF workers; what was parsed so far is still saved
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	code := exitOK
+	var entries []CauseListEntry
+courtLoop:
+	for _, court := range selected {
+		courtID := court.Info().ID
+		courtDates := dates
+		scope := backfillScope(courtID, opts)
+		done := make(map[string]DateCheckpoint)
+		if command == "backfill" {
+			if checkpoints != nil {
+				if done, err = checkpoints.Load(scope); err != nil {
+					log.Printf("Failed to load checkpoints of %s: %v", courtID, err)
+					code = firstFailureCode(code, exitFailure)
+					continue
+				}
+			}
+			if !opts.restart {
+				pending := pendingDates(courtDates, done)
+				if skipped := len(courtDates) - len(pending); skipped > 0 {
+					log.Printf("Backfill %s: resuming, %d of %d dates already done", courtID, skipped, len(courtDates))
+				}
+				courtDates = pending
+			}
+			if len(courtDates) == 0 {
+				log.Printf("Backfill %s: every date of the range is done, nothing to do", courtID)
+				continue
+			}
+		}
+
+		for _, date := range courtDates {
+			if runCtx.Err() != nil {
+				log.Printf("Interrupted, stopping before %s %s", courtID, date.Format(hitDateLayout))
+				code = firstFailureCode(code, exitFailure)
+				break courtLoop
+			}
+			dateLists, dateEntries, dateCode := runDate(runCtx, court, command, opts, date)
+			entries = append(entries, dateEntries...)
+			code = firstFailureCode(code, dateCode)
+
+			// An interrupted date is left as it was so the next run retries it
+			if command == "backfill" && runCtx.Err() == nil {
+				cp := newDateCheckpoint(date, done[date.Format("2006-01-02")], dateCode, dateLists, len(dateEntries))
+				done[cp.Date] = cp
+				if checkpoints != nil {
+					if err := checkpoints.Save(scope, cp); err != nil {
+						log.Printf("Failed to save checkpoint of %s %s: %v", courtID, cp.Date, err)
+						code = firstFailureCode(code, exitSaveFailed)
+					}
+				}
+			}
+		}
+		if command == "backfill" {
+			logCheckpoints(done)
+		}
+	}
+
+	stats := fetcher.Stats()
+	log.Printf("HTTP: %d requests, %d attempts, %d retries, %d failed, %d bytes",
+		stats.Requests, stats.Attempts, stats.Retries, stats.Failures, stats.Bytes)
+	runSummary.Print(os.Stdout)
+
+	if command == "export" || command == "backfill" {
+		Scraped_data_final = entries
+		if err := saveToCSV(opts.out, Scraped_data_final); err != nil {
+			log.Printf("Failed to save to CSV: %s", err)
+			return firstFailureCode(code, exitSaveFailed)
+		}
+		fmt.Printf("Data saved to %s\n", opts.out)
+	}
+	return code
+}
+
+// setupScraper creates the fetcher, session, stores and notifiers from appConfig.
+// Every command but parse writes to Redis and, with a DSN, the database
+func setupScraper(command string) int {
+	// Every request shares one cookie jar, which holds the sci.gov.in session
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		log.Printf("Failed to create cookie jar: %v", err)
+		return exitFailure
+	}
+	fetcher = NewFetcher(appConfig.Fetch, &http.Client{Jar: jar})
+	if sciSession, err = NewSession(fetcher, jar, appConfig.Scraper); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	if err := setupListTypes(appConfig.Scraper); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	if blobStore, err = newBlobStore(appConfig.Storage); err != nil {
+		log.Printf("Failed to set up blob store: %v", err)
+		return exitFailure
+	}
+	if command != "parse" {
+		conn, err := NewRedisConnection(appConfig.Redis)
+		if err != nil {
+			log.Printf("Failed to connect to Redis: %v", err)
+			return exitCodeOf(err)
+		}
+		causeListRepo = NewCauseListRepository(conn, appConfig.Redis)
+		if appConfig.Database.DSN != "" {
+			if causeListStore, err = openCauseListStore(); err != nil {
+				log.Printf("Failed to set up database: %v", err)
+				return exitFailure
+			}
+		}
+	}
+
+	if watchlist, err = newWatchlistStore(appConfig.Watchlist); err != nil {
+		log.Printf("Failed to open watchlist: %v", err)
+		return exitFailure
+	}
+	if notifiers, err = newNotifiers(appConfig.Notify); err != nil {
+		log.Printf("Failed to set up notifiers: %v", err)
+		return exitFailure
+	}
+	if events, err = newEventBus(appConfig.Daemon); err != nil {
+		log.Printf("Failed to set up event sinks: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// runDate runs one command for the cause lists of a court on a single hearing
+// date. It returns how many cause lists the date has, its entries and exit code
+func runDate(runCtx context.Context, court Court, command string, opts runOptions, date time.Time) (int, []CauseListEntry, int) {
+	courtID := court.Info().ID
+	hitDate := date.Format(hitDateLayout)
+	log.Printf("Processing hearing date %s of %s", hitDate, courtID)
+
+	// Every date starts with the lists of its own page so ranges don't mix their PDFs
+	lists, err := court.DiscoverLists(runCtx, ListQuery{
+		Date:     date,
+		Archived: opts.archived,
+		Search:   opts.search,
+		ListType: opts.listType,
+		CourtNo:  opts.courtNo,
+		Bench:    opts.bench,
+	})
+	code := exitOK
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) && lists != nil {
+		log.Printf("Causelist for %s %s: %v", courtID, hitDate, err)
+		code = exitSaveFailed
+	} else if err != nil {
+		log.Printf("Failed to get causelist for %s %s: %v", courtID, hitDate, err)
+		runSummary.RecordPage(courtID+" "+hitDate, err)
+		return 0, nil, exitCodeOf(err)
+	}
+	lists = listsOn(lists, date, opts.search)
+	if len(lists) == 0 {
+		log.Printf("No cause lists of %s for %s", courtID, hitDate)
+		return 0, nil, code
+	}
+
+	entries, storeCode := storeCauseLists(runCtx, command, date, lists, nil)
+	return len(lists), entries, firstFailureCode(code, storeCode)
+}
+
+// listsOn keeps the cause lists heard on date. Without a search the court's
+// page lists whatever it published last, which must not be saved under
+// another date; lists without a hearing date are only kept from a search,
+// which the court answered for the date
+func listsOn(lists map[string]CauseList, date time.Time, search bool) map[string]CauseList {
+	want, _ := newHearingDate(date.Year(), date.Month(), date.Day())
+	kept := make(map[string]CauseList, len(lists))
+	var skipped []string
+	for pdfID, causeList := range lists {
+		if causeList.DateOfHearing.Equal(want) || (search && causeList.DateOfHearing.IsZero()) {
+			kept[pdfID] = causeList
+		} else {
+			skipped = append(skipped, pdfID)
+		}
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		log.Printf("Skipping %d cause lists not heard on %s: %s", len(skipped), want, strings.Join(skipped, ", "))
+	}
+	return kept
+}
+
+// storeCauseLists saves the cause lists of a date and, unless command is
+// fetch, parses their PDFs, checks the watchlist and saves the entries. PDFs
+// in fetched were downloaded already and are not downloaded again
+func storeCauseLists(runCtx context.Context, command string, date time.Time, causeLists map[string]CauseList, fetched map[string]*downloadedPDF) ([]CauseListEntry, int) {
+	hitDate := date.Format(hitDateLayout)
+	code := exitOK
+	if command != "parse" {
+		if err := saveCauseListToRedis(causeLists); err != nil {
+			log.Printf("Failed to save causelist for %s to Redis: %v", hitDate, err)
+			code = firstFailureCode(code, exitSaveFailed)
+		}
+	}
+	if command == "fetch" {
+		if causeListStore != nil {
+			if _, err := causeListStore.SaveCauseLists(causeLists); err != nil {
+				log.Printf("Failed to save causelist for %s to the database: %v", hitDate, err)
+				code = firstFailureCode(code, exitSaveFailed)
+			}
+		}
+		return nil, code
+	}
+
+	// Failed PDFs only fail the date beyond scraper.failure_threshold
+	entries, versions, err := parseCauselistPDFData(runCtx, causeLists, fetched)
+	var pdfErrs *PDFErrors
+	if errors.As(err, &pdfErrs) {
+		log.Printf("Causelist PDFs for %s: %v", hitDate, err)
+		if pdfErrs.Exceeds(appConfig.Scraper.FailureThreshold) {
+			code = firstFailureCode(code, pdfErrs.ExitCode())
+		} else {
+			log.Printf("Failed PDFs for %s are within the failure threshold", hitDate)
+		}
+	} else if err != nil {
+		log.Printf("Failed to parse causelist PDFs for %s: %v", hitDate, err)
+		code = firstFailureCode(code, exitParseFailed)
+	}
+	fmt.Printf("Parsed %d entries for %s\n", len(entries), hitDate)
+
+	if err := checkWatchlist(runCtx, date.Format("2006-01-02"), causeLists, entries); err != nil {
+		log.Printf("Failed to notify watchlist matches for %s: %v", hitDate, err)
+		code = firstFailureCode(code, exitNotifyFailed)
+	}
+
+	if command == "parse" {
+		return entries, code
+	}
+
+	// Only the PDFs that parsed replace their saved entries
+	parsed := make(map[string]CauseList, len(versions))
+	for pdfID := range versions {
+		parsed[pdfID] = causeLists[pdfID]
+	}
+	updates, err := newVersions(parsed, versions)
+	if err != nil {
+		log.Printf("Failed to read cause list versions for %s: %v", hitDate, err)
+		code = firstFailureCode(code, exitSaveFailed)
+	}
+	if err := causeListRepo.SaveParsed(parsed, entries, updates); err != nil {
+		log.Printf("Failed to save entries for %s to Redis: %v", hitDate, err)
+		code = firstFailureCode(code, exitSaveFailed)
+	} else {
+		publishVersionChanges(runCtx, parsed, entries, updates)
+	}
+	if causeListStore != nil {
+		if err := causeListStore.SaveEntries(parsed, entries); err != nil {
+			log.Printf("Failed to save entries for %s to the database: %v", hitDate, err)
+			code = firstFailureCode(code, exitSaveFailed)
+		}
+	}
+	return entries, code
+}
+
+// openCauseListStore connects to the configured database, migrating it if enabled
+func openCauseListStore() (*CauseListStore, error) {
+	store, err := NewCauseListStore(appConfig.Database)
+	if err != nil {
+		return nil, err
+	}
+	if appConfig.Database.AutoMigrate {
+		if err := store.Migrate(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// runMigrate implements the "migrate" command
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	c, err := cf.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if c.Database.DSN == "" {
+		fmt.Fprintln(os.Stderr, "database.dsn (DATABASE_URL or --db) is required")
+		return exitUsage
+	}
+	appConfig = c
+
+	store, err := NewCauseListStore(appConfig.Database)
+	if err == nil {
+		err = store.Migrate()
+	}
+	if err != nil {
+		log.Printf("Migration failed: %v", err)
+		return exitFailure
+	}
+	fmt.Println("Database schema is up to date")
+	return exitOK
+}
+
+// selectDates resolves the --date or --from/--to flags into a list of dates
+func selectDates(opts runOptions, now time.Time) ([]time.Time, error) {
+	if opts.from == "" && opts.to == "" {
+		if opts.date == "" {
+			return nil, fmt.Errorf("a --date or a --from/--to range is required")
+		}
+		date, err := parseDateFlag(opts.date, now)
+		if err != nil {
+			return nil, err
+		}
+		return []time.Time{date}, nil
+	}
+
+	if opts.from == "" || opts.to == "" {
+		return nil, fmt.Errorf("both --from and --to are required for a date range")
+	}
+	from, err := parseDateFlag(opts.from, now)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateFlag(opts.to, now)
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("--to %s is before --from %s", opts.to, opts.from)
+	}
+
+	var dates []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
+
+// parseDateFlag parses a date given on the command line
+func parseDateFlag(value string, now time.Time) (time.Time, error) {
+	// Hearing dates are IST dates wherever the host runs
+	now = now.In(istLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, istLocation)
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	for _, layout := range []string{hitDateLayout, "2006-01-02"} {
+		if date, err := time.ParseInLocation(layout, value, istLocation); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected MM/DD/YYYY, YYYY-MM-DD, today or tomorrow", value)
+}
+
+// firstFailureCode returns current unless it is OK, so a run keeps the first
+// failure code it sees
+func firstFailureCode(current, next int) int {
+	if current != exitOK {
+		return current
+	}
+	return next
+}
+
+// setupLicense registers the unipdf metered key used for PDF text extraction
+func setupLicense() error {
+	return license.SetMeteredKey(appConfig.Unipdf.LicenseKey)
+}
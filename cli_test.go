@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateFlagInIST(t *testing.T) {
+	// 20:00 UTC on the 16th is already the 17th in India
+	now := time.Date(2024, 10, 16, 20, 0, 0, 0, time.UTC)
+	for value, want := range map[string]string{"today": "2024-10-17", "tomorrow": "2024-10-18"} {
+		date, err := parseDateFlag(value, now)
+		if err != nil {
+			t.Fatalf("%s: %v", value, err)
+		}
+		if got := hearingDateOf(date).String(); got != want {
+			t.Errorf("%s is %s, want %s", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CourtInfo describes a court the scraper supports
+type CourtInfo struct {
+	ID         string // Registry key, selected with --court, e.g. "sci"
+	Name       string // e.g. "Supreme Court of India"
+	Code       string // Prefix of its document IDs, see causeListDocumentID
+	ArchiveDir string // Directory of its archived pages and PDFs under causelist/pdf/
+
+	Selectors PageSelectors // Listing table of its cause list page, see parseListingTable
+}
+
+// ListQuery selects the cause lists DiscoverLists returns
+type ListQuery struct {
+	Date     time.Time // Hearing date
+	Archived string    // Archived listing page (file path or blob store key) to read instead of fetching
+
+	// Search filters, for courts with a search form. See CauseListQuery
+	Search   bool
+	ListType string
+	CourtNo  string
+	Bench    string
+}
+
+// Court scrapes the cause lists of one court. The scraper discovers the cause
+// list PDFs of a date, then fetches and parses each of them
+type Court interface {
+	Info() CourtInfo
+
+	// DiscoverLists returns the cause lists published for the query, by PDF
+	// ID. Every CauseList has Court set to the court's ID. When the lists were
+	// found but the listing page could not be archived, the lists are returned
+	// with a *StorageError
+	DiscoverLists(ctx context.Context, q ListQuery) (map[string]CauseList, error)
+
+	// FetchList downloads the PDF of a cause list
+	FetchList(ctx context.Context, pdfID string, causeList CauseList) (*downloadedPDF, error)
+
+	// ParseList extracts the entries and header date of a fetched cause list PDF
+	ParseList(pdfID string, pdfData []byte) (*ParsedList, error)
+}
+
+// ParsedList is what ParseList reads from a cause list PDF
+type ParsedList struct {
+	Entries     []CauseListEntry
+	HearingDate HearingDate // Date in the PDF header, zero when it has none
+}
+
+// courts holds every supported court by ID
+var courts = courtRegistry(
+	supremeCourt{},
+	delhiHighCourt{},
+)
+
+// courtRegistry maps courts to their IDs
+func courtRegistry(list ...Court) map[string]Court {
+	registry := make(map[string]Court, len(list))
+	for _, court := range list {
+		registry[court.Info().ID] = court
+	}
+	return registry
+}
+
+// courtByID returns the registered court with the ID
+func courtByID(id string) (Court, error) {
+	court, ok := courts[strings.ToLower(id)]
+	if !ok {
+		return nil, fmt.Errorf("unknown court %q (supported: %s)", id, strings.Join(courtIDs(), ", "))
+	}
+	return court, nil
+}
+
+// courtIDs returns the IDs of the registered courts, sorted
+func courtIDs() []string {
+	ids := make([]string, 0, len(courts))
+	for id := range courts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// selectedCourts returns the courts of scraper.courts, in order
+func selectedCourts() ([]Court, error) {
+	var selected []Court
+	for _, id := range appConfig.Scraper.Courts {
+		court, err := courtByID(id)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, court)
+	}
+	return selected, nil
+}
+
+// CourtID returns the court of a cause list. Cause lists saved before the
+// scraper supported several courts are Supreme Court lists
+func (c CauseList) CourtID() string {
+	if c.Court == "" {
+		return supremeCourtID
+	}
+	return c.Court
+}
+
+// courtInfo returns the info of a registered court. A court that is not
+// registered, e.g. one of a newer version that saved a document, uses its ID
+// for its code and archive directory
+func courtInfo(id string) CourtInfo {
+	if court, ok := courts[id]; ok {
+		return court.Info()
+	}
+	return CourtInfo{ID: id, Name: id, Code: id, ArchiveDir: id}
+}
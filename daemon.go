@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// runDaemon implements the "daemon" command: it polls the cause list page on
+// every schedule and parses and saves only the PDFs it has not seen before,
+// publishing an event for each
+func runDaemon(args []string) int {
+	var once bool
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	fs.BoolVar(&once, "once", false, "poll once and exit instead of following the schedules")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	c, err := cf.load()
+	if err == nil {
+		err = c.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	appConfig = c
+	if appConfig.Scraper.PDFSource == "archive" {
+		fmt.Fprintln(os.Stderr, "the daemon downloads newly published PDFs and cannot read them from the archive")
+		return exitUsage
+	}
+
+	var schedules []*cronSchedule
+	for _, expr := range appConfig.Daemon.Schedules {
+		schedule, err := parseCron(expr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if code := setupScraper("daemon"); code != exitOK {
+		return code
+	}
+	if err := setupLicense(); err != nil {
+		log.Printf("Failed to set metered key: %s", err)
+		return exitFailure
+	}
+
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	code := pollCauseLists(runCtx)
+	if once {
+		return code
+	}
+	for {
+		next := nextRun(schedules, time.Now())
+		log.Printf("Daemon: next poll at %s", next.Format(time.RFC3339))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-runCtx.Done():
+			timer.Stop()
+			log.Printf("Daemon: stopping")
+			return exitOK
+		case <-timer.C:
+		}
+		pollCauseLists(runCtx)
+	}
+}
+
+// nextRun returns the earliest next run of the schedules after now
+func nextRun(schedules []*cronSchedule, now time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range schedules {
+		if t := schedule.Next(now); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// pollCauseLists polls every selected court once, see pollCourt, and prints
+// the summary of the poll
+func pollCauseLists(runCtx context.Context) int {
+	now := time.Now()
+	runSummary = newRunSummary()
+
+	code := exitOK
+	selected, err := selectedCourts()
+	if err != nil {
+		log.Printf("Daemon: %v", err)
+		return exitUsage
+	}
+	for _, court := range selected {
+		if runCtx.Err() != nil {
+			break
+		}
+		code = firstFailureCode(code, pollCourt(runCtx, court, now))
+	}
+
+	stats := fetcher.Stats()
+	log.Printf("HTTP: %d requests, %d attempts, %d retries, %d failed, %d bytes",
+		stats.Requests, stats.Attempts, stats.Retries, stats.Failures, stats.Bytes)
+	runSummary.Print(os.Stdout)
+	return code
+}
+
+// pollCourt fetches the cause lists of a court once and processes the PDFs
+// that are not in the seen set, by hearing date. A PDF is marked seen once it
+// parsed, so one that failed is tried again on the next poll. With
+// daemon.recheck_seen, seen PDFs of upcoming hearings are downloaded again and
+// processed if their content changed, from the copy that was compared
+func pollCourt(runCtx context.Context, court Court, now time.Time) int {
+	courtID := court.Info().ID
+	code := exitOK
+	lists, err := court.DiscoverLists(runCtx, ListQuery{Date: now})
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) && lists != nil {
+		log.Printf("Daemon: causelist of %s: %v", courtID, err)
+		code = exitSaveFailed
+	} else if err != nil {
+		log.Printf("Daemon: failed to get causelist of %s: %v", courtID, err)
+		runSummary.RecordPage(courtID+" "+now.Format(hitDateLayout), err)
+		return exitCodeOf(err)
+	}
+
+	pdfIDs := sortedKeys(lists)
+	seen, err := causeListRepo.SeenPDFs(pdfIDs)
+	if err != nil {
+		log.Printf("Daemon: %v", err)
+		return exitFailure
+	}
+	today := hearingDateOf(now)
+	limiter := newHostRateLimiter(appConfig.Scraper.RateLimit)
+	byDate := make(map[string]map[string]CauseList)
+	rechecked := make(map[string]*downloadedPDF)
+	for _, pdfID := range pdfIDs {
+		causeList := lists[pdfID]
+		if seen[pdfID] {
+			// A corrected PDF may be re-uploaded at the same URL until the hearing
+			if !appConfig.Daemon.RecheckSeen || causeList.DateOfHearing.Before(today.Time) {
+				continue
+			}
+			pdf, changed, err := pdfChanged(runCtx, limiter, pdfID, causeList)
+			if err != nil {
+				log.Printf("Daemon: failed to recheck %s: %v", pdfID, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			log.Printf("Daemon: %s was re-uploaded with new content", pdfID)
+			rechecked[pdfID] = pdf
+		}
+		dateOfHearing := causeList.DateOfHearing.String()
+		if byDate[dateOfHearing] == nil {
+			byDate[dateOfHearing] = make(map[string]CauseList)
+		}
+		byDate[dateOfHearing][pdfID] = causeList
+	}
+	if len(byDate) == 0 {
+		log.Printf("Daemon: no new or changed cause lists of %s (%d seen)", courtID, len(pdfIDs))
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, dateOfHearing := range dates {
+		if runCtx.Err() != nil {
+			break
+		}
+		date := now
+		if hearingDate, err := parseHearingDate(dateOfHearing); err == nil && !hearingDate.IsZero() {
+			date = hearingDate.Time
+		} else {
+			log.Printf("Daemon: unknown hearing date %q, using today", dateOfHearing)
+		}
+		code = firstFailureCode(code, processPublished(runCtx, date, byDate[dateOfHearing], rechecked))
+	}
+	return code
+}
+
+// processPublished parses and saves the new and re-uploaded cause lists of one
+// hearing date and marks those that parsed seen. A new one that parsed is
+// published as causelist.published; a re-uploaded one, parsed from its copy in
+// rechecked, only as the causelist.changed of publishVersionChanges
+func processPublished(runCtx context.Context, date time.Time, published map[string]CauseList, rechecked map[string]*downloadedPDF) int {
+	log.Printf("Daemon: %d new or changed cause lists for %s", len(published), date.Format("2006-01-02"))
+	entries, code := storeCauseLists(runCtx, "export", date, published, rechecked)
+
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.PDFID]++
+	}
+	var parsed []string
+	for _, pdfID := range sortedKeys(published) {
+		// A PDF that failed is left for the next poll
+		if outcome, ok := runSummary.PDF(pdfID); !ok || !outcome.Parsed {
+			continue
+		}
+		parsed = append(parsed, pdfID)
+		if rechecked[pdfID] != nil {
+			continue
+		}
+		causeList := published[pdfID]
+		events.Publish(runCtx, Event{
+			Type:          EventCauseListPublished,
+			DocumentID:    causeListDocumentID(pdfID, causeList),
+			PDFID:         pdfID,
+			DateOfHearing: causeList.DateOfHearing.String(),
+			ListType:      causeList.Description,
+			PDFLink:       causeList.PDFLink,
+			Entries:       counts[pdfID],
+		})
+	}
+	if err := causeListRepo.MarkSeen(parsed); err != nil {
+		log.Printf("Daemon: %v", err)
+		code = firstFailureCode(code, exitSaveFailed)
+	}
+	return code
+}
+
+// sortedKeys returns the keys of a cause list map in order
+func sortedKeys(causeLists map[string]CauseList) []string {
+	keys := make([]string, 0, len(causeLists))
+	for key := range causeLists {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
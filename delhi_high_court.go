@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// delhiHighCourtID is the registry ID of the Delhi High Court
+const delhiHighCourtID = "dhc"
+
+// delhiHighCourt scrapes the daily cause lists published on
+// delhihighcourt.nic.in. Its cause list page is a table with one row per PDF:
+// serial number, title, date and a download link. A saved page and the text
+// layers of its PDFs are in testdata/
+type delhiHighCourt struct{}
+
+func (delhiHighCourt) Info() CourtInfo {
+	return CourtInfo{
+		ID:         delhiHighCourtID,
+		Name:       "Delhi High Court",
+		Code:       "dhc",
+		ArchiveDir: "delhi_high_court",
+		Selectors: PageSelectors{
+			Table:       "table",
+			Header:      "thead th",
+			Row:         "tbody tr",
+			Cell:        "td",
+			Link:        "a[href$='.pdf' i]",
+			DateColumn:  "date",
+			TitleColumn: "title",
+		},
+	}
+}
+
+// DiscoverLists fetches and archives the cause list page, or reads an archived
+// one, and returns every PDF listed on it. The page has no search form
+func (c delhiHighCourt) DiscoverLists(ctx context.Context, q ListQuery) (map[string]CauseList, error) {
+	pageURL := appConfig.Scraper.DHCURL
+	if q.Search {
+		return nil, errors.New("the Delhi High Court cause list page has no search form")
+	}
+	if q.Archived != "" {
+		body, archivedURL, err := readArchivedPage(q.Archived, pageURL)
+		if err != nil {
+			return nil, &ParseError{Source: q.Archived, Err: err}
+		}
+		return parseDelhiHighCourtPage(archivedURL, body)
+	}
+
+	resp, err := fetcher.Get(ctx, pageURL, nil)
+	if err != nil {
+		return nil, &FetchError{URL: pageURL, Err: err}
+	}
+	_, _, archiveErr := archiveCauselistPage(c.Info().ArchiveDir, q.Date.Format(hitDateLayout), resp.URL, resp.Body)
+	lists, err := parseDelhiHighCourtPage(resp.URL, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if archiveErr != nil {
+		return lists, archiveErr
+	}
+	return lists, nil
+}
+
+// FetchList downloads a cause list PDF
+func (delhiHighCourt) FetchList(ctx context.Context, pdfID string, causeList CauseList) (*downloadedPDF, error) {
+	return downloadPDF(ctx, causeList.PDFLink)
+}
+
+// ParseList extracts the text of a cause list PDF and parses its entries
+func (delhiHighCourt) ParseList(pdfID string, pdfData []byte) (*ParsedList, error) {
+	pdfText, err := extractTextFromPDF(pdfData)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseDelhiHighCourtText(pdfID, pdfText)
+	if err != nil {
+		return nil, err
+	}
+	hearingDate, _ := pdfHeaderDate(pdfText)
+	return &ParsedList{Entries: entries, HearingDate: hearingDate}, nil
+}
+
+// dhcTitleDatePattern matches the date at the end of a cause list title,
+// e.g. " for 17.10.2024"
+var dhcTitleDatePattern = regexp.MustCompile(`(?i)\s*(?:for|dated)?\s*\d{2}[./-]\d{2}[./-]\d{4}.*$`)
+
+// parseDelhiHighCourtPage extracts the cause list PDFs of the cause list page,
+// by PDF ID ("2024-10-17/cl_17102024"), using the court's page selectors.
+// Relative links are resolved against pageURL. A page whose layout does not
+// match the selectors is a *ParseError wrapping a *LayoutError
+func parseDelhiHighCourtPage(pageURL string, body []byte) (map[string]CauseList, error) {
+	rows, err := parseListingTable(delhiHighCourtID, pageURL, body)
+	if err != nil {
+		return nil, &ParseError{Source: "cause list page", Err: err}
+	}
+
+	lists := make(map[string]CauseList)
+	for _, row := range rows {
+		fromCell, _ := findDate(row.Date)
+		fromTitle, _ := findDate(row.Title)
+		dateOfHearing, problems := resolveHearingDate(
+			dateSource{name: "date cell", date: fromCell},
+			dateSource{name: "title", date: fromTitle},
+		)
+		description := strings.ToUpper(dhcTitleDatePattern.ReplaceAllString(row.Title, ""))
+		if description == "" {
+			description = "CAUSE LIST"
+		}
+
+		for _, link := range row.Links {
+			if dateOfHearing.IsZero() {
+				log.Printf("Delhi High Court: skipping %s, no hearing date in its row", link)
+				continue
+			}
+			name := path.Base(link)
+			pdfID := dateOfHearing.String() + "/" + strings.TrimSuffix(name, path.Ext(name))
+			reportDateProblems(pdfID, problems)
+			lists[pdfID] = CauseList{
+				DateOfHearing: dateOfHearing,
+				Description:   description,
+				PDFLink:       link,
+				Court:         delhiHighCourtID,
+				Columns:       row.Columns,
+			}
+		}
+	}
+	log.Printf("Delhi High Court: %d cause lists on the page", len(lists))
+	return lists, nil
+}
+
+// Lines of a Delhi High Court cause list PDF. Each court starts with its
+// number and bench, followed by section headings and numbered items:
+//
+//	COURT NO. 01 (ROOM NO. 001)
+//	HON'BLE THE CHIEF JUSTICE
+//	FRESH MATTERS
+//	1.    W.P.(C) 14016/2024      PETITIONER            ADVOCATES
+//	                              Vs.
+//	                              RESPONDENT            ADVOCATES
+//	      CM APPL. 58712/2024
+var (
+	dhcCourtPattern       = regexp.MustCompile(`(?i)^COURT\s+NO\.?\s*:?\s*(\d+)`)
+	dhcJudgePattern       = regexp.MustCompile(`(?i)^HON'?BLE\b`)
+	dhcItemPattern        = regexp.MustCompile(`^(\d+(?:\.\d+)?)\.?\s+([A-Z][A-Z.()&/-]*(?:\s[A-Z.()&/-]+)*?)\s*(\d+/\d{4})(?:\s+(.*))?$`)
+	dhcApplicationPattern = regexp.MustCompile(`^(CM\s+APPL\.|CRL\.M\.A\.|I\.A\.)\s*(\d+/\d{4})$`)
+	dhcPageNoisePattern   = regexp.MustCompile(`(?i)^(?:IN THE HIGH COURT OF DELHI|CAUSE LIST FOR|PAGE\s+\d+\s+OF\s+\d+$)`)
+)
+
+// parseDelhiHighCourtText extracts the entries of the text of one Delhi High
+// Court cause list PDF. Item numbers restart in every court; "2.1" is
+// connected to item 2. Text without any court heading is an error, as the
+// layout changed
+func parseDelhiHighCourtText(pdfID, text string) ([]CauseListEntry, error) {
+	var entries []CauseListEntry
+	var courtNo, section string
+	var judges []string
+	var courts int
+
+	var current *CauseListEntry
+	var block []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		var rest []string
+		for _, line := range block {
+			if m := dhcApplicationPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				current.IANumbers = append(current.IANumbers, strings.Join(strings.Fields(m[1]), " ")+" "+m[2])
+				continue
+			}
+			rest = append(rest, line)
+		}
+		details := parseItemDetails(strings.Join(rest, "\n"))
+		current.Petitioner = details.Petitioner
+		current.Respondent = details.Respondent
+		current.PetitionerAdvocates = details.PetitionerAdvocates
+		current.RespondentAdvocates = details.RespondentAdvocates
+		entries = append(entries, *current)
+		current, block = nil, nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || dhcPageNoisePattern.MatchString(trimmed):
+			continue
+		case dhcCourtPattern.MatchString(trimmed):
+			flush()
+			courtNo = strings.TrimLeft(dhcCourtPattern.FindStringSubmatch(trimmed)[1], "0")
+			judges, section = nil, ""
+			courts++
+		case dhcJudgePattern.MatchString(trimmed) && current == nil:
+			judges = append(judges, trimmed)
+		case sectionHeadingPattern.MatchString(trimmed):
+			flush()
+			section = strings.Join(strings.Fields(trimmed), " ")
+		case dhcItemPattern.MatchString(trimmed):
+			flush()
+			m := dhcItemPattern.FindStringSubmatch(trimmed)
+			caseNo := strings.Join(strings.Fields(m[2]), " ") + " " + m[3]
+			current = &CauseListEntry{
+				Sno:         m[1],
+				CaseNo:      caseNo,
+				CaseNoMap:   caseNo,
+				Judges:      judges,
+				CourtNo:     courtNo,
+				Section:     section,
+				ConnectedTo: mainItemOf(m[1], false),
+				PDFID:       pdfID,
+			}
+			block = []string{m[4]}
+		case current != nil:
+			block = append(block, line)
+		}
+	}
+	flush()
+
+	if courts == 0 {
+		return nil, errors.New("no court headings found, the PDF layout may have changed")
+	}
+	return entries, nil
+}
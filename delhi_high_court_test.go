@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// The saved cause list page and the text layers of its PDFs in testdata/. The
+// text layers are the PDF fixtures: extracting them needs a unipdf license
+const (
+	dhcTestPage    = "testdata/delhi_high_court/cause_list.html"
+	dhcTestPDFDir  = "testdata/causelist/pdf/delhi_high_court"
+	dhcTestPageURL = "https://delhihighcourt.nic.in/web/cause-lists/cause-list"
+)
+
+func TestParseDelhiHighCourtPage(t *testing.T) {
+	body, err := os.ReadFile(dhcTestPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists, err := parseDelhiHighCourtPage(dhcTestPageURL, body)
+	if err != nil {
+		t.Fatalf("parseDelhiHighCourtPage: %v", err)
+	}
+
+	want := map[string]struct {
+		date        string
+		description string
+		link        string
+	}{
+		"2024-10-17/cl_17102024":   {"2024-10-17", "CAUSE LIST", "https://delhihighcourt.nic.in/files/cause-list/2024/10/cl_17102024.pdf"},
+		"2024-10-17/supl_17102024": {"2024-10-17", "SUPPLEMENTARY CAUSE LIST", "https://delhihighcourt.nic.in/files/cause-list/2024/10/supl_17102024.pdf"},
+		"2024-10-18/adv_18102024":  {"2024-10-18", "ADVANCE CAUSE LIST", "https://delhihighcourt.nic.in/files/cause-list/2024/10/adv_18102024.pdf"},
+	}
+	if len(lists) != len(want) {
+		t.Fatalf("got %d cause lists, want %d: %v", len(lists), len(want), sortedKeys(lists))
+	}
+	for pdfID, w := range want {
+		got, ok := lists[pdfID]
+		if !ok {
+			t.Errorf("%s: missing", pdfID)
+			continue
+		}
+		if got.DateOfHearing.String() != w.date {
+			t.Errorf("%s: date %q, want %q", pdfID, got.DateOfHearing, w.date)
+		}
+		if got.Description != w.description {
+			t.Errorf("%s: description %q, want %q", pdfID, got.Description, w.description)
+		}
+		if got.PDFLink != w.link {
+			t.Errorf("%s: link %q, want %q", pdfID, got.PDFLink, w.link)
+		}
+		if got.Court != delhiHighCourtID {
+			t.Errorf("%s: court %q, want %q", pdfID, got.Court, delhiHighCourtID)
+		}
+	}
+}
+
+func TestParseDelhiHighCourtText(t *testing.T) {
+	tests := []struct {
+		pdfID   string
+		date    string
+		entries int
+		courts  []string // Court numbers, in order of first appearance
+	}{
+		{"2024-10-17/cl_17102024", "2024-10-17", 6, []string{"1", "5"}},
+		{"2024-10-17/supl_17102024", "2024-10-17", 1, []string{"3"}},
+		{"2024-10-18/adv_18102024", "2024-10-18", 2, []string{"2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pdfID, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join(dhcTestPDFDir, tt.pdfID+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := parseDelhiHighCourtText(tt.pdfID, string(text))
+			if err != nil {
+				t.Fatalf("parseDelhiHighCourtText: %v", err)
+			}
+			if len(entries) != tt.entries {
+				t.Errorf("got %d entries, want %d", len(entries), tt.entries)
+			}
+			var courts []string
+			for _, entry := range entries {
+				if !slices.Contains(courts, entry.CourtNo) {
+					courts = append(courts, entry.CourtNo)
+				}
+				if entry.PDFID != tt.pdfID {
+					t.Errorf("item %s: PDF ID %q, want %q", entry.Sno, entry.PDFID, tt.pdfID)
+				}
+			}
+			if !slices.Equal(courts, tt.courts) {
+				t.Errorf("courts %v, want %v", courts, tt.courts)
+			}
+			if date, _ := pdfHeaderDate(string(text)); date.String() != tt.date {
+				t.Errorf("header date %q, want %q", date, tt.date)
+			}
+		})
+	}
+}
+
+func TestParseDelhiHighCourtTextConnectedItems(t *testing.T) {
+	pdfID := "2024-10-17/cl_17102024"
+	text, err := os.ReadFile(filepath.Join(dhcTestPDFDir, pdfID+".txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := parseDelhiHighCourtText(pdfID, string(text))
+	if err != nil {
+		t.Fatalf("parseDelhiHighCourtText: %v", err)
+	}
+
+	byItem := make(map[string]CauseListEntry)
+	for _, entry := range entries {
+		if entry.CourtNo == "1" {
+			byItem[entry.Sno] = entry
+		}
+	}
+	connected, ok := byItem["2.1"]
+	if !ok {
+		t.Fatalf("item 2.1 of court 1 missing, got %v", entries)
+	}
+	if connected.ConnectedTo != "2" {
+		t.Errorf("item 2.1 connected to %q, want %q", connected.ConnectedTo, "2")
+	}
+	if connected.CaseNo != "W.P.(C) 9102/2023" {
+		t.Errorf("item 2.1 case %q, want %q", connected.CaseNo, "W.P.(C) 9102/2023")
+	}
+	if main := byItem["2"]; main.ConnectedTo != "" {
+		t.Errorf("item 2 connected to %q, want none", main.ConnectedTo)
+	}
+	if first := byItem["1"]; !slices.Equal(first.IANumbers, []string{"CM APPL. 58712/2024", "CM APPL. 58713/2024"}) {
+		t.Errorf("item 1 applications %v", first.IANumbers)
+	}
+}
+
+func TestParseDelhiHighCourtTextWithoutCourts(t *testing.T) {
+	if _, err := parseDelhiHighCourtText("2024-10-17/x", "SOME OTHER DOCUMENT\n1. W.P.(C) 1/2024 A Vs. B"); err == nil {
+		t.Error("text without court headings parsed without an error")
+	}
+}
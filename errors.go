@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// FetchError reports something that could not be downloaded from the court site
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetching %s failed: %v", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// ParseError reports a cause list page or PDF that could not be parsed
+type ParseError struct {
+	Source string // PDF ID, or "cause list page"
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parsing %s failed: %v", e.Source, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// StorageError reports a failed read or write of Redis, the database or the blob store
+type StorageError struct {
+	Op  string // e.g. "connect", "archive", "read"
+	Key string // Address, key or file involved
+	Err error
+}
+
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("%s %s failed: %v", e.Op, e.Key, e.Err)
+}
+
+func (e *StorageError) Unwrap() error { return e.Err }
+
+// exitCodeOf returns the exit code matching the type of err
+func exitCodeOf(err error) int {
+	var fetchErr *FetchError
+	var parseErr *ParseError
+	var storageErr *StorageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &fetchErr):
+		return exitFetchFailed
+	case errors.As(err, &parseErr):
+		return exitParseFailed
+	case errors.As(err, &storageErr):
+		return exitSaveFailed
+	default:
+		return exitFailure
+	}
+}
+
+// PDFErrors collects the PDFs of one parse that failed, by PDF ID. A PDF that
+// parsed but could not be archived is in it with a *StorageError
+type PDFErrors struct {
+	Total  int // PDFs in the parse
+	Errors map[string]error
+}
+
+func (e *PDFErrors) Error() string {
+	ids := e.ids()
+	return fmt.Sprintf("%d of %d PDFs failed, first %s: %v", len(ids), e.Total, ids[0], e.Errors[ids[0]])
+}
+
+// ids returns the failed PDF IDs in order
+func (e *PDFErrors) ids() []string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Exceeds tells whether more than threshold, a fraction, of the PDFs failed
+func (e *PDFErrors) Exceeds(threshold float64) bool {
+	return float64(len(e.Errors)) > threshold*float64(e.Total)
+}
+
+// ExitCode returns the exit code of the first failed PDF
+func (e *PDFErrors) ExitCode() int {
+	return exitCodeOf(e.Errors[e.ids()[0]])
+}
+
+// PDFOutcome is how one PDF of a run went
+type PDFOutcome struct {
+	PDFID   string
+	Parsed  bool
+	Entries int
+	Err     error // Why it failed, or why a parsed PDF was not archived
+}
+
+// RunSummary lists the outcome of every PDF and cause list page of a run
+type RunSummary struct {
+	mu    sync.Mutex
+	pdfs  map[string]PDFOutcome
+	pages map[string]error // Failed cause list pages, by hit date
+
+	unknownListTypes map[string]string   // PDF IDs of unknown list type codes, by code
+	dateProblems     map[string][]string // Missing or conflicting hearing dates, by PDF ID
+}
+
+// runSummary is the summary of the current run, or daemon poll
+var runSummary = newRunSummary()
+
+func newRunSummary() *RunSummary {
+	return &RunSummary{
+		pdfs:             make(map[string]PDFOutcome),
+		pages:            make(map[string]error),
+		unknownListTypes: make(map[string]string),
+		dateProblems:     make(map[string][]string),
+	}
+}
+
+// RecordPDF records the outcome of a PDF, replacing an earlier one
+func (s *RunSummary) RecordPDF(outcome PDFOutcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pdfs[outcome.PDFID] = outcome
+}
+
+// RecordPage records a cause list page that could not be fetched or parsed
+func (s *RunSummary) RecordPage(hitDate string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[hitDate] = err
+}
+
+// RecordUnknownListType records a PDF whose file name has no code of the list
+// type catalogue
+func (s *RunSummary) RecordUnknownListType(code, pdfID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unknownListTypes[code] = pdfID
+}
+
+// RecordDateProblem records a missing or conflicting hearing date of a PDF
+func (s *RunSummary) RecordDateProblem(pdfID, problem string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dateProblems[pdfID] = append(s.dateProblems[pdfID], problem)
+}
+
+// PDF returns the recorded outcome of a PDF
+func (s *RunSummary) PDF(pdfID string) (PDFOutcome, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	outcome, ok := s.pdfs[pdfID]
+	return outcome, ok
+}
+
+// Print writes the summary, one line per PDF and failed page
+func (s *RunSummary) Print(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.pdfs))
+	var parsed int
+	for id, outcome := range s.pdfs {
+		ids = append(ids, id)
+		if outcome.Parsed {
+			parsed++
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) == 0 && len(s.pages) == 0 && len(s.unknownListTypes) == 0 && len(s.dateProblems) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Summary: %d of %d PDFs parsed, %d failed\n", parsed, len(ids), len(ids)-parsed)
+	for _, id := range ids {
+		outcome := s.pdfs[id]
+		switch {
+		case !outcome.Parsed:
+			fmt.Fprintf(w, "  failed  %s: %v\n", id, outcome.Err)
+		case outcome.Err != nil:
+			fmt.Fprintf(w, "  ok      %s: %d entries, %v\n", id, outcome.Entries, outcome.Err)
+		default:
+			fmt.Fprintf(w, "  ok      %s: %d entries\n", id, outcome.Entries)
+		}
+	}
+
+	dates := make([]string, 0, len(s.pages))
+	for date := range s.pages {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		fmt.Fprintf(w, "  failed  cause list of %s: %v\n", date, s.pages[date])
+	}
+
+	codes := make([]string, 0, len(s.unknownListTypes))
+	for code := range s.unknownListTypes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "  unknown list type %q, e.g. %s: add it to the list types\n", code, s.unknownListTypes[code])
+	}
+
+	ids = ids[:0]
+	for id := range s.dateProblems {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, problem := range s.dateProblems[id] {
+			fmt.Fprintf(w, "  date    %s: %s\n", id, problem)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventCauseListPublished = "causelist.published" // A cause list PDF not seen before was parsed
+	EventCauseListChanged   = "causelist.changed"   // A cause list PDF was re-uploaded with new content
+)
+
+// Event is something the daemon noticed, sent to every EventSink
+type Event struct {
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	DocumentID    string    `json:"document_id"` // See causeListDocumentID
+	PDFID         string    `json:"pdf_id"`
+	DateOfHearing string    `json:"date_of_hearing"`
+	ListType      string    `json:"list_type"`
+	PDFLink       string    `json:"pdf_link"`
+	Entries       int       `json:"entries"`
+
+	// Set on causelist.changed
+	Version  int           `json:"version,omitempty"`
+	Hash     string        `json:"hash,omitempty"`
+	PrevHash string        `json:"prev_hash,omitempty"`
+	Changes  []EntryChange `json:"changes,omitempty"`
+}
+
+// EventSink receives the events published on an EventBus
+type EventSink interface {
+	Name() string
+	Handle(ctx context.Context, event Event) error
+}
+
+// EventBus delivers every published event to its sinks, in order
+type EventBus struct {
+	mu    sync.Mutex
+	sinks []EventSink
+}
+
+// events is the bus the daemon publishes to, set up from appConfig.Daemon
+var events = &EventBus{}
+
+// newEventBus creates a bus with the sinks named in the daemon config
+func newEventBus(cfg DaemonConfig) (*EventBus, error) {
+	bus := &EventBus{}
+	for _, name := range cfg.EventSinks {
+		switch name {
+		case "stdout":
+			bus.Subscribe(&StdoutEventSink{w: os.Stdout})
+		case "webhook":
+			bus.Subscribe(&WebhookEventSink{url: cfg.EventWebhookURL})
+		default:
+			return nil, fmt.Errorf("unknown event sink %q", name)
+		}
+	}
+	return bus, nil
+}
+
+// Subscribe adds a sink to the bus
+func (b *EventBus) Subscribe(sink EventSink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Publish sends the event to every sink. A failing sink is logged and does
+// not keep the event from the others
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sink := range b.sinks {
+		if err := sink.Handle(ctx, event); err != nil {
+			log.Printf("Failed to deliver %s event to the %s sink: %v", event.Type, sink.Name(), err)
+		}
+	}
+}
+
+// StdoutEventSink prints every event as a line of JSON
+type StdoutEventSink struct {
+	w io.Writer
+}
+
+func (s *StdoutEventSink) Name() string { return "stdout" }
+
+// Handle writes the event
+func (s *StdoutEventSink) Handle(ctx context.Context, event Event) error {
+	return json.NewEncoder(s.w).Encode(event)
+}
+
+// WebhookEventSink POSTs every event as JSON through the shared fetcher
+type WebhookEventSink struct {
+	url string
+}
+
+func (w *WebhookEventSink) Name() string { return "webhook" }
+
+// Handle posts the event and expects a 2xx response
+func (w *WebhookEventSink) Handle(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := fetcher.Do(req); err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	return nil
+}
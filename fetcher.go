@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Fetcher is the HTTP client every network call of the scraper goes through.
+// It retries network errors, 5xx and 429 responses with exponential backoff
+// and jitter, honours Retry-After, caps the body size and counts attempts
+type Fetcher struct {
+	client       *http.Client
+	maxAttempts  int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	maxBodyBytes int64
+	userAgent    string
+
+	requests atomic.Int64
+	attempts atomic.Int64
+	retries  atomic.Int64
+	failures atomic.Int64
+	bytes    atomic.Int64
+}
+
+// FetchResponse is a response whose body has been read in full
+type FetchResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	URL        string // Final URL after redirects
+	Attempts   int
+}
+
+// StatusError is returned when the server answers with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "server returned " + e.Status
+}
+
+// FetchStats counts the work done by a Fetcher
+type FetchStats struct {
+	Requests int64 // Calls to Do
+	Attempts int64 // HTTP round trips, including retries
+	Retries  int64 // Attempts after the first one of a request
+	Failures int64 // Requests that failed after every attempt
+	Bytes    int64 // Response body bytes read
+}
+
+// fetcher is the shared Fetcher, set up from appConfig.Fetch by the CLI
+var fetcher = NewFetcher(defaultConfig().Fetch, nil)
+
+// NewFetcher creates a Fetcher; client may be nil for a plain client with cfg.Timeout
+func NewFetcher(cfg FetchConfig, client *http.Client) *Fetcher {
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Timeout = cfg.Timeout
+	return &Fetcher{
+		client:       client,
+		maxAttempts:  cfg.MaxAttempts,
+		baseDelay:    cfg.BaseDelay,
+		maxDelay:     cfg.MaxDelay,
+		maxBodyBytes: cfg.MaxBodyBytes,
+		userAgent:    cfg.UserAgent,
+	}
+}
+
+// Get fetches url with the given extra headers
+func (f *Fetcher) Get(ctx context.Context, url string, header http.Header) (*FetchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return f.Do(req)
+}
+
+// Do sends req, retrying as described on Fetcher, and returns the response
+// once it is 2xx. A request with a body must be replayable (req.GetBody set,
+// as http.NewRequest does for in-memory bodies) to be retried
+func (f *Fetcher) Do(req *http.Request) (*FetchResponse, error) {
+	f.requests.Add(1)
+	if req.Header.Get("User-Agent") == "" && f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			f.retries.Add(1)
+			if req.Body != nil && req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, f.fail(fmt.Errorf("failed to rewind request body: %w", err))
+				}
+				req.Body = body
+			}
+		}
+
+		f.attempts.Add(1)
+		resp, retryAfter, err := f.attempt(req)
+		if err == nil {
+			resp.Attempts = attempt
+			return resp, nil
+		}
+		lastErr = err
+		if retryAfter < 0 || attempt >= f.maxAttempts || req.Context().Err() != nil {
+			break
+		}
+		if req.Body != nil && req.GetBody == nil {
+			break
+		}
+
+		delay := f.backoff(attempt)
+		if retryAfter > delay {
+			delay = min(retryAfter, f.maxDelay)
+		}
+		log.Printf("%s %s: %v, retrying in %s (attempt %d of %d)", req.Method, req.URL, err, delay.Round(time.Millisecond), attempt+1, f.maxAttempts)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, f.fail(fmt.Errorf("%s %s: %w", req.Method, req.URL, req.Context().Err()))
+		}
+	}
+	return nil, f.fail(fmt.Errorf("%s %s: %w", req.Method, req.URL, lastErr))
+}
+
+// attempt makes one round trip. retryAfter is negative when the error is not
+// worth retrying, and otherwise the delay the server asked for (0 if none)
+func (f *Fetcher) attempt(req *http.Request) (*FetchResponse, time.Duration, error) {
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, -1, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBodyBytes+1))
+	f.bytes.Add(int64(len(body)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > f.maxBodyBytes {
+		return nil, -1, fmt.Errorf("response body exceeds %d bytes", f.maxBodyBytes)
+	}
+	return &FetchResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		URL:        resp.Request.URL.String(),
+	}, 0, nil
+}
+
+// backoff returns the delay before the attempt after attempt: the base delay
+// doubled per attempt, capped at the max delay, with full jitter
+func (f *Fetcher) backoff(attempt int) time.Duration {
+	delay := f.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > f.maxDelay {
+		delay = f.maxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// fail counts a request that gave up
+func (f *Fetcher) fail(err error) error {
+	f.failures.Add(1)
+	return err
+}
+
+// Stats returns the counters of the fetcher
+func (f *Fetcher) Stats() FetchStats {
+	return FetchStats{
+		Requests: f.requests.Load(),
+		Attempts: f.attempts.Load(),
+		Retries:  f.retries.Load(),
+		Failures: f.failures.Load(),
+		Bytes:    f.bytes.Load(),
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
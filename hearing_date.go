@@ -0,0 +1,242 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// istLocation is India Standard Time, the zone of every hearing date. India
+// has no daylight saving, so a fixed zone needs no tzdata
+var istLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+// hearingDateLayout is how a HearingDate is written in keys, documents,
+// database rows and JSON
+const hearingDateLayout = "2006-01-02"
+
+// HearingDate is the date a cause list is heard, at midnight IST. The zero
+// value is an unknown date and is written as ""
+type HearingDate struct {
+	time.Time
+}
+
+// newHearingDate returns the date, or false when it does not exist
+func newHearingDate(year int, month time.Month, day int) (HearingDate, bool) {
+	t := time.Date(year, month, day, 0, 0, 0, 0, istLocation)
+	if t.Year() != year || t.Month() != month || t.Day() != day {
+		return HearingDate{}, false
+	}
+	return HearingDate{t}, true
+}
+
+// hearingDateOf returns the IST date of t
+func hearingDateOf(t time.Time) HearingDate {
+	ist := t.In(istLocation)
+	date, _ := newHearingDate(ist.Year(), ist.Month(), ist.Day())
+	return date
+}
+
+// parseHearingDate parses a date written as "2006-01-02"; "" is the zero date
+func parseHearingDate(s string) (HearingDate, error) {
+	if s == "" {
+		return HearingDate{}, nil
+	}
+	t, err := time.ParseInLocation(hearingDateLayout, s, istLocation)
+	if err != nil {
+		return HearingDate{}, fmt.Errorf("invalid hearing date %q: %w", s, err)
+	}
+	return HearingDate{t}, nil
+}
+
+func (d HearingDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.In(istLocation).Format(hearingDateLayout)
+}
+
+// Equal tells whether both are the same date; two unknown dates are equal
+func (d HearingDate) Equal(other HearingDate) bool {
+	return d.String() == other.String()
+}
+
+func (d HearingDate) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *HearingDate) UnmarshalText(text []byte) error {
+	date, err := parseHearingDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// MarshalJSON and UnmarshalJSON replace the ones of the embedded time.Time
+func (d HearingDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *HearingDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid hearing date %s: %w", data, err)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// Scan reads a date column; NULL is the zero date
+func (d *HearingDate) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*d = HearingDate{}
+	case time.Time:
+		// The driver returns the calendar date at midnight UTC
+		date, _ := newHearingDate(v.Year(), v.Month(), v.Day())
+		*d = date
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a hearing date", value)
+	}
+	return nil
+}
+
+// Value writes the date as "2006-01-02", or NULL when it is unknown
+func (d HearingDate) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// Dates as the courts print them: "2024-10-16" in links, "16-10-2024",
+// "16/10/2024" or "16.10.2024" in pages and PDFs, and "16TH OCTOBER, 2024" or
+// "16 October 2024" in PDF headers
+var (
+	isoDatePattern     = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	numericDatePattern = regexp.MustCompile(`\b(\d{1,2})[-/.](\d{1,2})[-/.](\d{4})\b`)
+	writtenDatePattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?:ST|ND|RD|TH)?\s+(JANUARY|FEBRUARY|MARCH|APRIL|MAY|JUNE|JULY|AUGUST|SEPTEMBER|OCTOBER|NOVEMBER|DECEMBER),?\s+(\d{4})\b`)
+)
+
+// findDate returns the first valid day-first date in text, numeric or written
+// out, whichever comes first
+func findDate(text string) (HearingDate, bool) {
+	var found HearingDate
+	at := -1
+	for _, m := range numericDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		day, _ := strconv.Atoi(text[m[2]:m[3]])
+		month, _ := strconv.Atoi(text[m[4]:m[5]])
+		year, _ := strconv.Atoi(text[m[6]:m[7]])
+		if date, ok := newHearingDate(year, time.Month(month), day); ok {
+			found, at = date, m[0]
+			break
+		}
+	}
+	for _, m := range writtenDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		if at >= 0 && m[0] > at {
+			break
+		}
+		day, _ := strconv.Atoi(text[m[2]:m[3]])
+		month, _ := time.Parse("January", text[m[4]:m[5]]) // Month names match in any case
+		year, _ := strconv.Atoi(text[m[6]:m[7]])
+		if date, ok := newHearingDate(year, month.Month(), day); ok {
+			return date, true
+		}
+	}
+	return found, at >= 0
+}
+
+// linkHearingDate returns the date of a "YYYY-MM-DD" path segment of a link
+func linkHearingDate(link string) (HearingDate, bool) {
+	for _, part := range strings.Split(link, "/") {
+		m := isoDatePattern.FindStringSubmatch(part)
+		if m == nil || m[0] != part {
+			continue
+		}
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		if date, ok := newHearingDate(year, time.Month(month), day); ok {
+			return date, true
+		}
+	}
+	return HearingDate{}, false
+}
+
+// headerLines is how many non-empty lines of a PDF's text are its header
+const headerLines = 15
+
+// pdfHeaderDate returns the first date in the header of a cause list PDF's text
+func pdfHeaderDate(text string) (HearingDate, bool) {
+	var header []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if header = append(header, line); len(header) == headerLines {
+			break
+		}
+	}
+	return findDate(strings.Join(header, "\n"))
+}
+
+// dateSource is where a hearing date was read from, e.g. the PDF link
+type dateSource struct {
+	name string
+	date HearingDate // Zero when the source had no date
+}
+
+// resolveHearingDate returns the date of the first source that has one, in
+// order of trust, and a problem for every source without a date or with a
+// different one
+func resolveHearingDate(sources ...dateSource) (HearingDate, []string) {
+	var date HearingDate
+	var from string
+	for _, s := range sources {
+		if !s.date.IsZero() {
+			date, from = s.date, s.name
+			break
+		}
+	}
+
+	var problems []string
+	for _, s := range sources {
+		switch {
+		case s.date.IsZero():
+			problems = append(problems, "no date in the "+s.name)
+		case !s.date.Equal(date):
+			problems = append(problems, fmt.Sprintf("the %s says %s, the %s %s", s.name, s.date, from, date))
+		}
+	}
+	return date, problems
+}
+
+// checkHeaderDate cross-checks the date in the header of a parsed PDF with
+// the hearing date its cause list was listed with
+func checkHeaderDate(pdfID string, causeList CauseList, header HearingDate) {
+	switch {
+	case header.IsZero():
+		reportDateProblems(pdfID, []string{"no date in the PDF header"})
+	case causeList.DateOfHearing.IsZero():
+		reportDateProblems(pdfID, []string{fmt.Sprintf("the PDF header says %s, the cause list has no date", header)})
+	case !header.Equal(causeList.DateOfHearing):
+		reportDateProblems(pdfID, []string{fmt.Sprintf("the PDF header says %s, the cause list %s", header, causeList.DateOfHearing)})
+	}
+}
+
+// reportDateProblems logs the date problems of a PDF and adds them to the run summary
+func reportDateProblems(pdfID string, problems []string) {
+	for _, problem := range problems {
+		log.Printf("Hearing date of %s: %s", pdfID, problem)
+		runSummary.RecordDateProblem(pdfID, problem)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ListType is one kind of cause list, e.g. the main list of the judges'
+// miscellaneous matters
+type ListType struct {
+	Code          string `yaml:"code" json:"code"`                   // Code in the PDF file name, e.g. "m_j_1"
+	Label         string `yaml:"label" json:"label"`                 // Saved as the cause list's Description
+	Bench         string `yaml:"bench" json:"bench"`                 // judge, chamber, registrar or single
+	Supplementary bool   `yaml:"supplementary" json:"supplementary"` // Added after the main list
+}
+
+// listTypeBenches are the bench types a ListType may have
+var listTypeBenches = []string{"judge", "chamber", "registrar", "single"}
+
+//go:embed list_types.yaml
+var defaultListTypes []byte
+
+// ListTypeCatalog finds the list type of a cause list PDF from its file name
+type ListTypeCatalog struct {
+	types []ListType
+	codes []*regexp.Regexp // Matches the code of types[i] as a whole word of a file name
+}
+
+// listTypes is the catalogue in use, the built-in one unless
+// scraper.list_types_file replaces it
+var listTypes = mustParseListTypes(defaultListTypes)
+
+func mustParseListTypes(raw []byte) *ListTypeCatalog {
+	catalog, err := parseListTypes(raw)
+	if err != nil {
+		panic(fmt.Sprintf("built-in list types: %v", err))
+	}
+	return catalog
+}
+
+// setupListTypes replaces the built-in catalogue with scraper.list_types_file, if set
+func setupListTypes(cfg ScraperConfig) error {
+	if cfg.ListTypesFile == "" {
+		return nil
+	}
+	catalog, err := loadListTypes(cfg.ListTypesFile)
+	if err != nil {
+		return err
+	}
+	listTypes = catalog
+	return nil
+}
+
+// loadListTypes reads a catalogue file
+func loadListTypes(file string) (*ListTypeCatalog, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read list types: %w", err)
+	}
+	catalog, err := parseListTypes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return catalog, nil
+}
+
+// parseListTypes parses and checks a YAML list of list types
+func parseListTypes(raw []byte) (*ListTypeCatalog, error) {
+	var types []ListType
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&types); err != nil {
+		return nil, fmt.Errorf("failed to parse list types: %w", err)
+	}
+
+	catalog := &ListTypeCatalog{}
+	seen := make(map[string]bool)
+	for i, t := range types {
+		t.Code = strings.ToLower(strings.TrimSpace(t.Code))
+		switch {
+		case t.Code == "":
+			return nil, fmt.Errorf("list type %d has no code", i+1)
+		case seen[t.Code]:
+			return nil, fmt.Errorf("list type %q is listed twice", t.Code)
+		case t.Label == "":
+			return nil, fmt.Errorf("list type %q has no label", t.Code)
+		case !slices.Contains(listTypeBenches, t.Bench):
+			return nil, fmt.Errorf("list type %q has bench %q, want one of %s", t.Code, t.Bench, strings.Join(listTypeBenches, ", "))
+		}
+		seen[t.Code] = true
+		catalog.types = append(catalog.types, t)
+		catalog.codes = append(catalog.codes, regexp.MustCompile(`(?:^|[^a-z0-9])`+regexp.QuoteMeta(t.Code)+`(?:[^a-z0-9]|$)`))
+	}
+	return catalog, nil
+}
+
+// Lookup returns the list type of a PDF link, by the code in its file name.
+// When several codes match the longest wins, so "m_cc_1" is never taken for
+// "m_c_1". Without a match it returns the lowercased file name, the unknown code
+func (c *ListTypeCatalog) Lookup(link string) (ListType, string, bool) {
+	name := strings.ToLower(path.Base(link))
+	name = strings.TrimSuffix(name, path.Ext(name))
+
+	best := -1
+	for i, code := range c.codes {
+		if code.MatchString(name) && (best < 0 || len(c.types[i].Code) > len(c.types[best].Code)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ListType{}, name, false
+	}
+	return c.types[best], c.types[best].Code, true
+}
+
+// ByLabel returns the list type saved with a cause list Description
+func (c *ListTypeCatalog) ByLabel(label string) (ListType, bool) {
+	for _, t := range c.types {
+		if t.Label == label {
+			return t, true
+		}
+	}
+	return ListType{}, false
+}
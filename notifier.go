@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Notifier sends a watchlist match report somewhere
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, report WatchlistReport) error
+}
+
+// notifiers are the configured notifiers, set up from appConfig.Notify
+var notifiers []Notifier
+
+// newNotifiers creates the notifiers named in the notify config
+func newNotifiers(cfg NotifyConfig) ([]Notifier, error) {
+	var list []Notifier
+	for _, name := range cfg.Notifiers {
+		switch name {
+		case "stdout":
+			list = append(list, &StdoutNotifier{w: os.Stdout})
+		case "smtp":
+			list = append(list, &SMTPNotifier{cfg: cfg.SMTP})
+		case "webhook":
+			list = append(list, &WebhookNotifier{cfg: cfg.Webhook})
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+	}
+	return list, nil
+}
+
+// dispatchReport sends the report through every notifier, even if one fails
+func dispatchReport(ctx context.Context, report WatchlistReport) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, report); err != nil {
+			errs = append(errs, fmt.Errorf("%s notifier: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StdoutNotifier prints the report
+type StdoutNotifier struct {
+	w io.Writer
+}
+
+func (s *StdoutNotifier) Name() string { return "stdout" }
+
+// Notify writes the report as a text table
+func (s *StdoutNotifier) Notify(ctx context.Context, report WatchlistReport) error {
+	_, err := fmt.Fprint(s.w, report.Text())
+	return err
+}
+
+// SMTPNotifier emails the report
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func (s *SMTPNotifier) Name() string { return "smtp" }
+
+// Notify sends the report as a plain text mail to every recipient
+func (s *SMTPNotifier) Notify(ctx context.Context, report WatchlistReport) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %d watched matters listed on %s\r\n", len(report.Matches), report.DateOfHearing)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(report.Text(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	if err := smtp.SendMail(addr, auth, s.cfg.From, s.cfg.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send mail through %s: %w", addr, err)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the report as JSON through the shared fetcher
+type WebhookNotifier struct {
+	cfg WebhookConfig
+}
+
+func (w *WebhookNotifier) Name() string { return "webhook" }
+
+// Notify posts the report and expects a 2xx response
+func (w *WebhookNotifier) Notify(ctx context.Context, report WatchlistReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if w.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.cfg.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if _, err := fetcher.Do(req); err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	return nil
+}
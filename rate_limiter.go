@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// hostRateLimiter spaces out requests to the same host so that each host sees
+// at most perSecond requests per second. A zero rate disables limiting
+type hostRateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time // Earliest time of the next request to each host
+}
+
+// newHostRateLimiter creates a limiter allowing perSecond requests per second per host
+func newHostRateLimiter(perSecond float64) *hostRateLimiter {
+	l := &hostRateLimiter{next: make(map[string]time.Time)}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// Wait blocks until a request to the host of rawURL may be sent, or ctx is done
+func (l *hostRateLimiter) Wait(ctx context.Context, rawURL string) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	// Reserve the next free slot of the host, then sleep until it comes
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(slot))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
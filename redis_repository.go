@@ -0,0 +1,508 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis key schema. Every value is JSON and every key shares the "causelist:"
+// prefix:
+//
+//	causelist:doc:{id}         CauseListDocument for one cause list PDF
+//	causelist:date:{date}      SET of document IDs listed for a hearing date, none for unknown dates
+//	causelist:type:{listType}  SET of document IDs of a list type (getDescription)
+//	causelist:ids              SET of every document ID
+//	causelist:entries:{id}     JSON array of the CauseListEntry values parsed from a document
+//	causelist:case:{caseKey}   HASH of CaseListing JSON for a normalized case number
+//	causelist:diary:{diaryKey} HASH of CaseListing JSON for a normalized diary number
+//	causelist:seen             SET of the PDF IDs (trimPDFLink) the daemon has parsed
+//	causelist:versions:{pdfID} LIST of PDFVersion JSON, oldest first
+//
+// Case and diary hashes are keyed by "{date}|{pdfID}|{sno}" so re-running a
+// date overwrites its listings instead of adding duplicates.
+//
+// Documents and their entries expire after redis.document_ttl and index sets after
+// redis.index_ttl; a zero TTL keeps the key forever
+const (
+	redisDocKeyPrefix   = "causelist:doc:"
+	redisDateKeyPrefix  = "causelist:date:"
+	redisTypeKeyPrefix  = "causelist:type:"
+	redisAllIDsKey      = "causelist:ids"
+	redisEntriesPrefix  = "causelist:entries:"
+	redisCaseKeyPrefix  = "causelist:case:"
+	redisDiaryKeyPrefix = "causelist:diary:"
+	redisSeenKey        = "causelist:seen"
+	redisVersionsPrefix = "causelist:versions:"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// CauseListDocument is the JSON stored for each cause list
+type CauseListDocument struct {
+	ID    string // See causeListDocumentID
+	PDFID string // Key of the PDF in the cause lists of a run, see trimPDFLink
+	CauseList
+}
+
+// undatedInID stands for an unknown hearing date in a document ID
+const undatedInID = "undated"
+
+// causeListDocumentID builds the ID of a cause list document, prefixed with
+// the code of its court ("10" for the Supreme Court)
+func causeListDocumentID(pdfID string, causeList CauseList) string {
+	date := causeList.DateOfHearing.String()
+	if date == "" {
+		date = undatedInID
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", courtInfo(causeList.CourtID()).Code, causeList.Description, date, pdfID)
+}
+
+// documentIDPattern splits a document ID into court code, list type, hearing date and PDF ID
+var documentIDPattern = regexp.MustCompile(`^([^-]+)-(.*?)-(\d{4}-\d{2}-\d{2}|` + undatedInID + `)-(.+)$`)
+
+// pdfIDOfDocument returns the PDF ID a document ID was built from
+func pdfIDOfDocument(id string) (string, bool) {
+	m := documentIDPattern.FindStringSubmatch(id)
+	if m == nil {
+		return "", false
+	}
+	return m[4], true
+}
+
+// CauseListRepository reads and writes cause lists in Redis using the key
+// schema above
+type CauseListRepository struct {
+	conn     *RedisConnection
+	docTTL   time.Duration
+	indexTTL time.Duration
+}
+
+// causeListRepo is the repository the scraper writes to, set up by the CLI
+var causeListRepo *CauseListRepository
+
+// NewCauseListRepository creates a repository on an open connection
+func NewCauseListRepository(conn *RedisConnection, cfg RedisConfig) *CauseListRepository {
+	return &CauseListRepository{
+		conn:     conn,
+		docTTL:   cfg.DocumentTTL,
+		indexTTL: cfg.IndexTTL,
+	}
+}
+
+// SaveCauseLists stores every cause list and its index entries in one
+// transaction and returns the document IDs, sorted
+func (r *CauseListRepository) SaveCauseLists(causeLists map[string]CauseList) ([]string, error) {
+	var ids []string
+	_, err := r.conn.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for pdfID, causeList := range causeLists {
+			doc := CauseListDocument{
+				ID:        causeListDocumentID(pdfID, causeList),
+				PDFID:     pdfID,
+				CauseList: causeList,
+			}
+			val, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("error marshalling cause list %s: %v", pdfID, err)
+			}
+			pipe.Set(ctx, redisDocKeyPrefix+doc.ID, val, r.docTTL)
+			if !causeList.DateOfHearing.IsZero() {
+				r.addToIndex(pipe, redisDateKeyPrefix+causeList.DateOfHearing.String(), doc.ID)
+			}
+			r.addToIndex(pipe, redisTypeKeyPrefix+causeList.Description, doc.ID)
+			r.addToIndex(pipe, redisAllIDsKey, doc.ID)
+			ids = append(ids, doc.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving cause lists to Redis: %w", err)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// addToIndex adds id to an index set and refreshes the set's TTL
+func (r *CauseListRepository) addToIndex(pipe redis.Pipeliner, key, id string) {
+	pipe.SAdd(ctx, key, id)
+	if r.indexTTL > 0 {
+		pipe.Expire(ctx, key, r.indexTTL)
+	}
+}
+
+// GetCauseList reads one cause list document by ID
+func (r *CauseListRepository) GetCauseList(id string) (*CauseListDocument, error) {
+	value, err := r.conn.client.Get(ctx, redisDocKeyPrefix+id).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("cause list %s: %w", id, ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting cause list from Redis: %w", err)
+	}
+
+	var doc CauseListDocument
+	if err := json.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("error unmarshalling cause list %s: %w", id, err)
+	}
+	return &doc, nil
+}
+
+// CauseListsByDate returns the cause lists of a hearing date, sorted by ID
+func (r *CauseListRepository) CauseListsByDate(date string) ([]CauseListDocument, error) {
+	return r.causeListsInSet(redisDateKeyPrefix + date)
+}
+
+// CauseListsByType returns the cause lists of a list type, sorted by ID
+func (r *CauseListRepository) CauseListsByType(listType string) ([]CauseListDocument, error) {
+	return r.causeListsInSet(redisTypeKeyPrefix + listType)
+}
+
+// AllCauseLists returns every stored cause list, sorted by ID
+func (r *CauseListRepository) AllCauseLists() ([]CauseListDocument, error) {
+	return r.causeListsInSet(redisAllIDsKey)
+}
+
+// causeListsInSet loads the documents whose IDs are members of an index set.
+// IDs whose document has expired are skipped
+func (r *CauseListRepository) causeListsInSet(setKey string) ([]CauseListDocument, error) {
+	ids, err := r.conn.client.SMembers(ctx, setKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading index %s from Redis: %w", setKey, err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisDocKeyPrefix + id
+	}
+	values, err := r.conn.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cause lists from Redis: %w", err)
+	}
+
+	docs := make([]CauseListDocument, 0, len(values))
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var doc CauseListDocument
+		if err := json.Unmarshal([]byte(str), &doc); err != nil {
+			return nil, fmt.Errorf("error unmarshalling cause list %s: %w", ids[i], err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// SaveParsed stores the entries parsed from causeLists, their case index and
+// the new versions of their PDFs in one transaction. The case index fields of
+// the entries of the previous versions are dropped first, so a failure leaves
+// the previous entries, index and version as they were
+func (r *CauseListRepository) SaveParsed(causeLists map[string]CauseList, entries []CauseListEntry, updates []versionUpdate) error {
+	_, err := r.conn.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, update := range updates {
+			r.removeCaseIndex(pipe, causeLists, update.stale)
+		}
+		if err := r.saveEntries(pipe, causeLists, entries); err != nil {
+			return err
+		}
+		if err := r.saveCaseIndex(pipe, causeLists, entries); err != nil {
+			return err
+		}
+		for _, update := range updates {
+			val, err := json.Marshal(update.version)
+			if err != nil {
+				return fmt.Errorf("error marshalling version: %v", err)
+			}
+			pipe.RPush(ctx, redisVersionsPrefix+update.pdfID, val)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error saving entries to Redis: %w", err)
+	}
+	return nil
+}
+
+// saveCaseIndex indexes every entry under its normalized case and diary numbers
+func (r *CauseListRepository) saveCaseIndex(pipe redis.Pipeliner, causeLists map[string]CauseList, entries []CauseListEntry) error {
+	for _, entry := range entries {
+		causeList, ok := causeLists[entry.PDFID]
+		if !ok {
+			continue
+		}
+		listing := newCaseListing(entry, causeList)
+		val, err := json.Marshal(listing)
+		if err != nil {
+			return fmt.Errorf("error marshalling case listing: %v", err)
+		}
+		field := listing.DateOfHearing + "|" + listing.PDFID + "|" + listing.Sno
+
+		var keys []string
+		for _, caseKey := range entryCaseNumberKeys(entry) {
+			keys = append(keys, redisCaseKeyPrefix+caseKey)
+		}
+		if diaryKey := normalizeDiaryNumber(entry.DiaryNo); diaryKey != "" {
+			keys = append(keys, redisDiaryKeyPrefix+diaryKey)
+		}
+		for _, key := range keys {
+			pipe.HSet(ctx, key, field, val)
+			if r.indexTTL > 0 {
+				pipe.Expire(ctx, key, r.indexTTL)
+			}
+		}
+	}
+	return nil
+}
+
+// removeCaseIndex drops the case and diary index fields of entries, e.g. those
+// of a previous version of a cause list
+func (r *CauseListRepository) removeCaseIndex(pipe redis.Pipeliner, causeLists map[string]CauseList, entries []CauseListEntry) {
+	for _, entry := range entries {
+		causeList, ok := causeLists[entry.PDFID]
+		if !ok {
+			continue
+		}
+		field := causeList.DateOfHearing.String() + "|" + entry.PDFID + "|" + entry.Sno
+		for _, caseKey := range entryCaseNumberKeys(entry) {
+			pipe.HDel(ctx, redisCaseKeyPrefix+caseKey, field)
+		}
+		if diaryKey := normalizeDiaryNumber(entry.DiaryNo); diaryKey != "" {
+			pipe.HDel(ctx, redisDiaryKeyPrefix+diaryKey, field)
+		}
+	}
+}
+
+// LookupCase returns the listings of a case number, optionally only on one
+// hearing date (YYYY-MM-DD)
+func (r *CauseListRepository) LookupCase(caseNo, date string) ([]CaseListing, error) {
+	caseKey := normalizeCaseNumber(caseNo)
+	if caseKey == "" {
+		return nil, fmt.Errorf("cannot parse case number %q", caseNo)
+	}
+	return r.listingsInHash(redisCaseKeyPrefix+caseKey, date)
+}
+
+// LookupDiary returns the listings of a diary number, optionally only on one
+// hearing date (YYYY-MM-DD)
+func (r *CauseListRepository) LookupDiary(diaryNo, date string) ([]CaseListing, error) {
+	diaryKey := normalizeDiaryNumber(diaryNo)
+	if diaryKey == "" {
+		return nil, fmt.Errorf("cannot parse diary number %q", diaryNo)
+	}
+	return r.listingsInHash(redisDiaryKeyPrefix+diaryKey, date)
+}
+
+// listingsInHash decodes the listings of a case or diary hash
+func (r *CauseListRepository) listingsInHash(key, date string) ([]CaseListing, error) {
+	values, err := r.conn.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s from Redis: %w", key, err)
+	}
+
+	var listings []CaseListing
+	for _, value := range values {
+		var listing CaseListing
+		if err := json.Unmarshal([]byte(value), &listing); err != nil {
+			return nil, fmt.Errorf("error unmarshalling case listing: %w", err)
+		}
+		if date != "" && listing.DateOfHearing != date {
+			continue
+		}
+		listings = append(listings, listing)
+	}
+	sortCaseListings(listings)
+	return listings, nil
+}
+
+// saveEntries stores the entries parsed from each cause list next to its document
+func (r *CauseListRepository) saveEntries(pipe redis.Pipeliner, causeLists map[string]CauseList, entries []CauseListEntry) error {
+	byPDF := make(map[string][]CauseListEntry)
+	for _, entry := range entries {
+		byPDF[entry.PDFID] = append(byPDF[entry.PDFID], entry)
+	}
+	for pdfID, causeList := range causeLists {
+		val, err := json.Marshal(byPDF[pdfID])
+		if err != nil {
+			return fmt.Errorf("error marshalling entries of %s: %v", pdfID, err)
+		}
+		pipe.Set(ctx, redisEntriesPrefix+causeListDocumentID(pdfID, causeList), val, r.docTTL)
+	}
+	return nil
+}
+
+// EntriesOf returns the entries parsed from a cause list document
+func (r *CauseListRepository) EntriesOf(id string) ([]CauseListEntry, error) {
+	value, err := r.conn.client.Get(ctx, redisEntriesPrefix+id).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting entries from Redis: %w", err)
+	}
+
+	var entries []CauseListEntry
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		return nil, fmt.Errorf("error unmarshalling entries of %s: %w", id, err)
+	}
+	return entries, nil
+}
+
+// CauseLists returns a page of the cause lists of a hearing date, or of every
+// date when date is empty
+func (r *CauseListRepository) CauseLists(date string, page Page) ([]CauseListDocument, int, error) {
+	var docs []CauseListDocument
+	var err error
+	if date != "" {
+		docs, err = r.CauseListsByDate(date)
+	} else {
+		docs, err = r.AllCauseLists()
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return paginate(docs, page), len(docs), nil
+}
+
+// CauseList returns a cause list document and its entries
+func (r *CauseListRepository) CauseList(id string) (*CauseListDocument, []CaseListing, error) {
+	doc, err := r.GetCauseList(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	listings, err := r.documentListings(*doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, listings, nil
+}
+
+// Entries returns a page of the entries matching filter. Case and diary
+// numbers are looked up in the case index, the case number first when both are
+// set; otherwise the documents of the date (or every document) are scanned.
+// The other fields, the diary number included, filter what was found
+func (r *CauseListRepository) Entries(filter EntryFilter, page Page) ([]CaseListing, int, error) {
+	var listings []CaseListing
+	var err error
+	switch {
+	case filter.CaseNo != "":
+		listings, err = r.LookupCase(filter.CaseNo, filter.Date)
+	case filter.DiaryNo != "":
+		listings, err = r.LookupDiary(filter.DiaryNo, filter.Date)
+	default:
+		var docs []CauseListDocument
+		if filter.Date != "" {
+			docs, err = r.CauseListsByDate(filter.Date)
+		} else {
+			docs, err = r.AllCauseLists()
+		}
+		for _, doc := range docs {
+			if err != nil {
+				break
+			}
+			var docListings []CaseListing
+			docListings, err = r.documentListings(doc)
+			listings = append(listings, docListings...)
+		}
+		sortCaseListings(listings)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listings = filter.apply(listings)
+	return paginate(listings, page), len(listings), nil
+}
+
+// documentListings returns the entries of a document as listings
+func (r *CauseListRepository) documentListings(doc CauseListDocument) ([]CaseListing, error) {
+	entries, err := r.EntriesOf(doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	listings := make([]CaseListing, 0, len(entries))
+	for _, entry := range entries {
+		listings = append(listings, newCaseListing(entry, doc.CauseList))
+	}
+	return listings, nil
+}
+
+// Ping checks that Redis is reachable
+func (r *CauseListRepository) Ping() error {
+	return r.conn.client.Ping(ctx).Err()
+}
+
+// SeenPDFs returns which of the PDF IDs were marked seen
+func (r *CauseListRepository) SeenPDFs(pdfIDs []string) (map[string]bool, error) {
+	seen := make(map[string]bool, len(pdfIDs))
+	if len(pdfIDs) == 0 {
+		return seen, nil
+	}
+	members := make([]interface{}, len(pdfIDs))
+	for i, id := range pdfIDs {
+		members[i] = id
+	}
+	found, err := r.conn.client.SMIsMember(ctx, redisSeenKey, members...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error reading seen PDFs from Redis: %w", err)
+	}
+	for i, id := range pdfIDs {
+		seen[id] = found[i]
+	}
+	return seen, nil
+}
+
+// MarkSeen adds PDF IDs to the seen set
+func (r *CauseListRepository) MarkSeen(pdfIDs []string) error {
+	if len(pdfIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(pdfIDs))
+	for i, id := range pdfIDs {
+		members[i] = id
+	}
+	if err := r.conn.client.SAdd(ctx, redisSeenKey, members...).Err(); err != nil {
+		return fmt.Errorf("error marking PDFs seen in Redis: %w", err)
+	}
+	return nil
+}
+
+// LatestVersion returns the latest version of a PDF, or nil if none was recorded
+func (r *CauseListRepository) LatestVersion(pdfID string) (*PDFVersion, error) {
+	value, err := r.conn.client.LIndex(ctx, redisVersionsPrefix+pdfID, -1).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting version from Redis: %w", err)
+	}
+	var version PDFVersion
+	if err := json.Unmarshal([]byte(value), &version); err != nil {
+		return nil, fmt.Errorf("error unmarshalling version of %s: %w", pdfID, err)
+	}
+	return &version, nil
+}
+
+// Versions returns the version history of a PDF, oldest first
+func (r *CauseListRepository) Versions(pdfID string) ([]PDFVersion, error) {
+	values, err := r.conn.client.LRange(ctx, redisVersionsPrefix+pdfID, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting versions from Redis: %w", err)
+	}
+	versions := make([]PDFVersion, 0, len(values))
+	for _, value := range values {
+		var version PDFVersion
+		if err := json.Unmarshal([]byte(value), &version); err != nil {
+			return nil, fmt.Errorf("error unmarshalling version of %s: %w", pdfID, err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
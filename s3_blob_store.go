@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3BlobStore keeps objects in S3 under the configured base path. Private
+// objects go to the private bucket; public ones to the public bucket, or the
+// private one when there is none, with the public ACL. The clients are built
+// once and shared by every call
+type S3BlobStore struct {
+	client       *s3.Client
+	presign      *s3.PresignClient
+	uploader     *manager.Uploader
+	bucket       string
+	publicBucket string
+	publicACL    string
+	cacheMaxAge  time.Duration
+	region       string
+	basePath     string
+}
+
+// NewS3BlobStore creates an S3BlobStore from the AWS config
+func NewS3BlobStore(cfg AWSConfig) (*S3BlobStore, error) {
+	awsCfg, err := loadAWSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS configuration: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg)
+	return &S3BlobStore{
+		client:  client,
+		presign: s3.NewPresignClient(client),
+		// Bodies larger than one part are uploaded in parts, in parallel
+		uploader: manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = cfg.MultipartPartSize
+		}),
+		bucket:       cfg.Bucket,
+		publicBucket: cfg.PublicBucket,
+		publicACL:    cfg.PublicACL,
+		cacheMaxAge:  cfg.CacheMaxAge,
+		region:       cfg.Region,
+		basePath:     cfg.BasePath,
+	}, nil
+}
+
+// objectKey prefixes key with the base path
+func (s *S3BlobStore) objectKey(key string) string {
+	return strings.TrimPrefix(s.keyPrefix()+cleanBlobKey(key), "/")
+}
+
+// keyPrefix is the base path with a trailing slash, or "" when there is none
+func (s *S3BlobStore) keyPrefix() string {
+	if base := cleanBlobKey(s.basePath); base != "" {
+		return base + "/"
+	}
+	return ""
+}
+
+// buckets returns the buckets an object may be in, the private one first
+func (s *S3BlobStore) buckets() []string {
+	if s.publicBucket == "" || s.publicBucket == s.bucket {
+		return []string{s.bucket}
+	}
+	return []string{s.bucket, s.publicBucket}
+}
+
+// Put uploads body to the bucket selected by opts.Public, in parts when it is
+// large. The content type is guessed from the key when not given
+func (s *S3BlobStore) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error {
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = getMimeType(key)
+	}
+	input := &s3.PutObjectInput{
+		Bucket:       aws.String(s.bucket),
+		Key:          aws.String(s.objectKey(key)),
+		Body:         body,
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String(fmt.Sprintf("private, max-age=%d", int(s.cacheMaxAge.Seconds()))),
+		Metadata:     opts.Metadata,
+	}
+	if opts.Public {
+		if s.publicBucket != "" {
+			input.Bucket = aws.String(s.publicBucket)
+		}
+		if s.publicACL != "" {
+			input.ACL = types.ObjectCannedACL(s.publicACL)
+		}
+		input.CacheControl = aws.String(fmt.Sprintf("public, max-age=%d", int(s.cacheMaxAge.Seconds())))
+	}
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get downloads the object; the caller closes the returned body
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	var err error
+	for _, bucket := range s.buckets() {
+		var out *s3.GetObjectOutput
+		out, err = s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(s.objectKey(key)),
+		})
+		if err == nil {
+			return out.Body, nil
+		}
+		if err = s.wrapError(key, err); !errors.Is(err, ErrBlobNotFound) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// Stat returns the object's size, content type and metadata
+func (s *S3BlobStore) Stat(ctx context.Context, key string) (BlobInfo, error) {
+	var err error
+	for _, bucket := range s.buckets() {
+		var out *s3.HeadObjectOutput
+		out, err = s.client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(s.objectKey(key)),
+		})
+		if err == nil {
+			return BlobInfo{
+				Key:          cleanBlobKey(key),
+				Size:         aws.ToInt64(out.ContentLength),
+				ContentType:  aws.ToString(out.ContentType),
+				LastModified: aws.ToTime(out.LastModified),
+				Metadata:     out.Metadata,
+			}, nil
+		}
+		if err = s.wrapError(key, err); !errors.Is(err, ErrBlobNotFound) {
+			return BlobInfo{}, err
+		}
+	}
+	return BlobInfo{}, err
+}
+
+// List returns every object whose key starts with prefix, in both buckets
+func (s *S3BlobStore) List(ctx context.Context, prefix string) ([]BlobInfo, error) {
+	fullPrefix := s.keyPrefix() + strings.TrimPrefix(prefix, "/")
+
+	var infos []BlobInfo
+	for _, bucket := range s.buckets() {
+		paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(fullPrefix),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+			}
+			for _, obj := range page.Contents {
+				key := strings.TrimPrefix(aws.ToString(obj.Key), s.keyPrefix())
+				infos = append(infos, BlobInfo{
+					Key:          key,
+					Size:         aws.ToInt64(obj.Size),
+					LastModified: aws.ToTime(obj.LastModified),
+				})
+			}
+		}
+	}
+	return infos, nil
+}
+
+// Delete removes the object from both buckets
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	for _, bucket := range s.buckets() {
+		_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(s.objectKey(key)),
+		})
+		if err != nil {
+			return s.wrapError(key, err)
+		}
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL of a private object valid for expires
+func (s *S3BlobStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return req.URL, nil
+}
+
+// PublicURL returns the plain URL of an object uploaded as public
+func (s *S3BlobStore) PublicURL(key string) string {
+	bucket := s.publicBucket
+	if bucket == "" {
+		bucket = s.bucket
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, s.region, s.objectKey(key))
+}
+
+// wrapError maps S3 "not found" errors to ErrBlobNotFound
+func (s *S3BlobStore) wrapError(key string, err error) error {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return fmt.Errorf("%s: %w", key, ErrBlobNotFound)
+	}
+	return fmt.Errorf("%s: %w", key, err)
+}
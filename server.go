@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// CauseListReader is the read side of the cause list storage served by the
+// API. CauseListStore (PostgreSQL) and CauseListRepository (Redis) implement it
+type CauseListReader interface {
+	CauseLists(date string, page Page) ([]CauseListDocument, int, error)
+	CauseList(id string) (*CauseListDocument, []CaseListing, error)
+	Entries(filter EntryFilter, page Page) ([]CaseListing, int, error)
+	Ping() error
+}
+
+// Page selects a slice of a result list
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// paginate returns the items of a page of list
+func paginate[T any](list []T, page Page) []T {
+	if page.Offset >= len(list) {
+		return nil
+	}
+	end := page.Offset + page.Limit
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[page.Offset:end]
+}
+
+// EntryFilter selects entries; empty fields match everything
+type EntryFilter struct {
+	CaseNo  string // Any spelling normalizeCaseNumber understands
+	DiaryNo string // Any spelling normalizeDiaryNumber understands
+	Court   string // Court number, case-insensitive
+	Judge   string // Part of a judge's name, case-insensitive
+	Date    string // Hearing date, YYYY-MM-DD
+}
+
+// apply keeps the listings that match the diary number, court, judge and date
+// of the filter. Case numbers are matched by the case index lookup
+func (f EntryFilter) apply(listings []CaseListing) []CaseListing {
+	diaryKey := normalizeDiaryNumber(f.DiaryNo)
+	var kept []CaseListing
+	for _, l := range listings {
+		if f.DiaryNo != "" && normalizeDiaryNumber(l.DiaryNo) != diaryKey {
+			continue
+		}
+		if f.Court != "" && !strings.EqualFold(l.CourtNo, f.Court) {
+			continue
+		}
+		if f.Judge != "" && !strings.Contains(strings.ToLower(l.JudgeName()), strings.ToLower(f.Judge)) {
+			continue
+		}
+		if f.Date != "" && l.DateOfHearing != f.Date {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
+
+// causeListJSON is a cause list as returned by the API
+type causeListJSON struct {
+	ID            string      `json:"id"`
+	PDFID         string      `json:"pdf_id"`
+	DateOfHearing string      `json:"date_of_hearing"`
+	ListType      string      `json:"list_type"`
+	BenchType     string      `json:"bench_type,omitempty"` // See ListType, empty for an unknown list type
+	Supplementary bool        `json:"supplementary,omitempty"`
+	PDFLink       string      `json:"pdf_link"`
+	Entries       []entryJSON `json:"entries,omitempty"`
+}
+
+func newCauseListJSON(doc CauseListDocument) causeListJSON {
+	listType, _ := listTypes.ByLabel(doc.Description)
+	return causeListJSON{
+		ID:            doc.ID,
+		PDFID:         doc.PDFID,
+		DateOfHearing: doc.DateOfHearing.String(),
+		ListType:      doc.Description,
+		BenchType:     listType.Bench,
+		Supplementary: listType.Supplementary,
+		PDFLink:       doc.PDFLink,
+	}
+}
+
+// entryJSON is an entry as returned by the API
+type entryJSON struct {
+	Sno                 string   `json:"sno"`
+	CaseNo              string   `json:"case_no,omitempty"`
+	CaseNoMap           string   `json:"case_no_map,omitempty"`
+	DiaryNo             string   `json:"diary_no,omitempty"`
+	DateOfHearing       string   `json:"date_of_hearing"`
+	ListType            string   `json:"list_type"`
+	Section             string   `json:"section,omitempty"`
+	CourtNo             string   `json:"court_no"`
+	Judges              []string `json:"judges"`
+	Petitioner          string   `json:"petitioner,omitempty"`
+	Respondent          string   `json:"respondent,omitempty"`
+	PetitionerAdvocates []string `json:"petitioner_advocates,omitempty"`
+	RespondentAdvocates []string `json:"respondent_advocates,omitempty"`
+	IANumbers           []string `json:"ia_numbers,omitempty"`
+	ConnectedTo         string   `json:"connected_to,omitempty"`
+	PDFID               string   `json:"pdf_id"`
+	PDFLink             string   `json:"pdf_link"`
+}
+
+func newEntryJSON(l CaseListing) entryJSON {
+	return entryJSON{
+		Sno:                 l.Sno,
+		CaseNo:              l.CaseNo,
+		CaseNoMap:           l.CaseNoMap,
+		DiaryNo:             l.DiaryNo,
+		DateOfHearing:       l.DateOfHearing,
+		ListType:            l.ListType,
+		Section:             l.Section,
+		CourtNo:             l.CourtNo,
+		Judges:              l.Judges,
+		Petitioner:          l.Petitioner,
+		Respondent:          l.Respondent,
+		PetitionerAdvocates: l.PetitionerAdvocates,
+		RespondentAdvocates: l.RespondentAdvocates,
+		IANumbers:           l.IANumbers,
+		ConnectedTo:         l.ConnectedTo,
+		PDFID:               l.PDFID,
+		PDFLink:             l.PDFLink,
+	}
+}
+
+func newEntriesJSON(listings []CaseListing) []entryJSON {
+	entries := make([]entryJSON, 0, len(listings))
+	for _, l := range listings {
+		entries = append(entries, newEntryJSON(l))
+	}
+	return entries
+}
+
+// pageJSON is the envelope of a paginated response
+type pageJSON struct {
+	Items  any `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// apiServer serves the cause list API from a CauseListReader
+type apiServer struct {
+	reader CauseListReader
+}
+
+// routes returns the API handler
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /causelists", s.handleCauseLists)
+	mux.HandleFunc("GET /causelists/{id...}", s.handleCauseList)
+	mux.HandleFunc("GET /entries", s.handleEntries)
+	return mux
+}
+
+func (s *apiServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if err := s.reader.Ping(); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *apiServer) handleCauseLists(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	date, err := parseDateParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	docs, total, err := s.reader.CauseLists(date, page)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	items := make([]causeListJSON, 0, len(docs))
+	for _, doc := range docs {
+		items = append(items, newCauseListJSON(doc))
+	}
+	writeJSON(w, http.StatusOK, pageJSON{Items: items, Total: total, Limit: page.Limit, Offset: page.Offset})
+}
+
+func (s *apiServer) handleCauseList(w http.ResponseWriter, r *http.Request) {
+	doc, listings, err := s.reader.CauseList(r.PathValue("id"))
+	if errors.Is(err, ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	out := newCauseListJSON(*doc)
+	out.Entries = newEntriesJSON(listings)
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *apiServer) handleEntries(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	date, err := parseDateParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q := r.URL.Query()
+	filter := EntryFilter{
+		CaseNo:  q.Get("case"),
+		DiaryNo: q.Get("diary"),
+		Court:   q.Get("court"),
+		Judge:   q.Get("judge"),
+		Date:    date,
+	}
+	if filter.CaseNo != "" && normalizeCaseNumber(filter.CaseNo) == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("cannot parse case number %q", filter.CaseNo))
+		return
+	}
+	if filter.DiaryNo != "" && normalizeDiaryNumber(filter.DiaryNo) == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("cannot parse diary number %q", filter.DiaryNo))
+		return
+	}
+
+	listings, total, err := s.reader.Entries(filter, page)
+	if err != nil {
+		s.internalError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, pageJSON{Items: newEntriesJSON(listings), Total: total, Limit: page.Limit, Offset: page.Offset})
+}
+
+func (s *apiServer) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+}
+
+// parsePage reads the limit and offset query parameters
+func parsePage(r *http.Request) (Page, error) {
+	page := Page{Limit: defaultPageLimit}
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, errors.New("offset must not be negative")
+		}
+		page.Offset = n
+	}
+	return page, nil
+}
+
+// parseDateParam reads the date query parameter as YYYY-MM-DD
+func parseDateParam(r *http.Request) (string, error) {
+	value := r.URL.Query().Get("date")
+	if value == "" {
+		return "", nil
+	}
+	date, err := parseDateFlag(value, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return date.Format("2006-01-02"), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// runServe implements the "serve" command
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	c, err := cf.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	appConfig = c
+	if err := setupListTypes(appConfig.Scraper); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	var reader CauseListReader
+	if appConfig.Database.DSN != "" {
+		store, err := openCauseListStore()
+		if err != nil {
+			log.Printf("Failed to set up database: %v", err)
+			return exitFailure
+		}
+		reader = store
+	} else {
+		conn, err := NewRedisConnection(appConfig.Redis)
+		if err != nil {
+			log.Printf("Failed to connect to Redis: %v", err)
+			return exitFailure
+		}
+		reader = NewCauseListRepository(conn, appConfig.Redis)
+	}
+
+	srv := &http.Server{
+		Addr:              appConfig.Server.Addr,
+		Handler:           (&apiServer{reader: reader}).routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	go func() {
+		<-stop.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("Serving the cause list API on %s", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Server failed: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
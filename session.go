@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Session keeps a sci.gov.in session in the cookie jar of the shared fetcher.
+// The session is opened by visiting the landing page, saved to a cookie file
+// between runs and opened again when the site reports it expired
+type Session struct {
+	fetcher    *Fetcher
+	jar        http.CookieJar
+	siteURL    *url.URL
+	cookieFile string
+	maxAge     time.Duration
+
+	mu          sync.Mutex
+	established bool
+}
+
+// sciSession is the session the scraper fetches sci.gov.in pages with
+var sciSession *Session
+
+// savedCookies is the content of the cookie file
+type savedCookies struct {
+	SavedAt time.Time         `json:"saved_at"`
+	Cookies map[string]string `json:"cookies"` // Name to value
+}
+
+// NewSession creates a session on f, whose client must use jar. Cookies saved
+// by an earlier run are loaded if they are younger than cfg.SessionMaxAge
+func NewSession(f *Fetcher, jar http.CookieJar, cfg ScraperConfig) (*Session, error) {
+	siteURL, err := url.Parse(cfg.LandingURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid landing page URL: %w", err)
+	}
+	s := &Session{
+		fetcher:    f,
+		jar:        jar,
+		siteURL:    siteURL,
+		cookieFile: cfg.CookieFile,
+		maxAge:     cfg.SessionMaxAge,
+	}
+	s.load()
+	return s, nil
+}
+
+// load restores the cookies of the cookie file, if it is recent enough
+func (s *Session) load() {
+	if s.cookieFile == "" {
+		return
+	}
+	raw, err := os.ReadFile(s.cookieFile)
+	if err != nil {
+		return
+	}
+	var saved savedCookies
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		log.Printf("Ignoring unreadable cookie file %s: %v", s.cookieFile, err)
+		return
+	}
+	if s.maxAge > 0 && time.Since(saved.SavedAt) > s.maxAge {
+		return
+	}
+	var cookies []*http.Cookie
+	for name, value := range saved.Cookies {
+		cookies = append(cookies, &http.Cookie{Name: name, Value: value, Path: "/"})
+	}
+	s.jar.SetCookies(s.siteURL, cookies)
+	s.established = len(cookies) > 0
+}
+
+// save writes the current cookies of the site to the cookie file
+func (s *Session) save() {
+	if s.cookieFile == "" {
+		return
+	}
+	saved := savedCookies{SavedAt: time.Now(), Cookies: make(map[string]string)}
+	for _, c := range s.jar.Cookies(s.siteURL) {
+		saved.Cookies[c.Name] = c.Value
+	}
+	out, err := json.Marshal(saved)
+	if err == nil {
+		err = os.WriteFile(s.cookieFile, out, 0600)
+	}
+	if err != nil {
+		log.Printf("Failed to save cookies to %s: %v", s.cookieFile, err)
+	}
+}
+
+// establish drops the current cookies and opens a new session from the landing page
+func (s *Session) establish(ctx context.Context) error {
+	var expired []*http.Cookie
+	for _, c := range s.jar.Cookies(s.siteURL) {
+		expired = append(expired, &http.Cookie{Name: c.Name, Path: "/", MaxAge: -1})
+	}
+	if len(expired) > 0 {
+		s.jar.SetCookies(s.siteURL, expired)
+	}
+
+	header := http.Header{}
+	header.Set("accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	if _, err := s.fetcher.Get(ctx, s.siteURL.String(), header); err != nil {
+		return fmt.Errorf("failed to open a session on %s: %w", s.siteURL, err)
+	}
+	if len(s.jar.Cookies(s.siteURL)) == 0 {
+		log.Printf("Landing page %s set no cookies", s.siteURL)
+	}
+	s.established = true
+	s.save()
+	return nil
+}
+
+// Get fetches url within the session, opening the session first if needed and
+// once more if the site answers with an expired session or login page
+func (s *Session) Get(ctx context.Context, rawURL string, header http.Header) (*FetchResponse, error) {
+	return s.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		return req, nil
+	})
+}
+
+// Submit sends form values within the session, as the query of a GET or the
+// url-encoded body of a POST
+func (s *Session) Submit(ctx context.Context, method, action string, values url.Values) (*FetchResponse, error) {
+	return s.do(ctx, func() (*http.Request, error) {
+		if method == http.MethodGet {
+			target, err := url.Parse(action)
+			if err != nil {
+				return nil, err
+			}
+			query := target.Query()
+			for name, v := range values {
+				query[name] = v
+			}
+			target.RawQuery = query.Encode()
+			return http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, action, strings.NewReader(values.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+}
+
+// do sends the request built by newRequest within the session
+func (s *Session) do(ctx context.Context, newRequest func() (*http.Request, error)) (*FetchResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.established {
+		if err := s.establish(ctx); err != nil {
+			return nil, err
+		}
+	}
+	req, err := newRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := s.fetcher.Do(req)
+	if !sessionExpired(resp, err) {
+		if err == nil {
+			s.save()
+		}
+		return resp, err
+	}
+
+	log.Printf("Session expired fetching %s, opening a new one", req.URL)
+	if err := s.establish(ctx); err != nil {
+		return nil, err
+	}
+	if req, err = newRequest(); err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err = s.fetcher.Do(req)
+	if err == nil && sessionExpired(resp, nil) {
+		return nil, fmt.Errorf("%s %s: session expired again after re-opening it", req.Method, req.URL)
+	}
+	return resp, err
+}
+
+// sessionExpiredMarkers are phrases of the pages sci.gov.in serves to a stale session
+var sessionExpiredMarkers = []string{
+	"session expired",
+	"session has expired",
+	"session timeout",
+	"invalid session",
+	"please login",
+}
+
+// sessionExpired tells whether a response is an expired session or login page
+func sessionExpired(resp *FetchResponse, err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden
+	}
+	if err != nil || resp == nil {
+		return false
+	}
+	if u, err := url.Parse(resp.URL); err == nil && strings.Contains(strings.ToLower(u.Path), "login") {
+		return true
+	}
+	// Only look at the start of the page; cause list pages are large
+	head := resp.Body
+	if len(head) > 16<<10 {
+		head = head[:16<<10]
+	}
+	lower := strings.ToLower(string(head))
+	for _, marker := range sessionExpiredMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,189 +1,266 @@
-package main
-
-import (
-	"encoding/csv"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"os"
-	"strings"
-	"time"
-
-	"github.com/joho/godotenv"
-	"github.com/unidoc/unipdf/v3/common/license"
-)
-
-var causeListMap = make(map[string]CauseList)
-
-func init() {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Error loading .env file")
-	}
-}
-
-var Scraped_data_final []CauseListEntry
-
-// Function to save Scraped_data_final to CSV
-func saveToCSV(fileName string, data []CauseListEntry) error {
-	// Create the CSV file
-	file, err := os.Create(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %v", err)
-	}
-	defer file.Close()
-
-	// Create a CSV writer
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write the CSV header
-	header := []string{"Sno", "CaseNo", "DiaryNo", "CaseNoMap", "JudgeName", "CourtNo"}
-	err = writer.Write(header)
-	if err != nil {
-		return fmt.Errorf("failed to write header to CSV: %v", err)
-	}
-
-	// Write the data rows
-	for _, entry := range data {
-		row := []string{entry.Sno, entry.CaseNo, entry.DiaryNo, entry.CaseNoMap, entry.JudgeName, entry.CourtNo}
-		err = writer.Write(row)
-		if err != nil {
-			return fmt.Errorf("failed to write row to CSV: %v", err)
-		}
-	}
-
-	return nil
-}
-
-func getSupremeCourtCauselistPDF(data map[string]string) map[string]string {
-	dirName := "./causelist/pdf/supreme_court"
-
-	// Create directory if it doesn't exist
-	err := os.MkdirAll(dirName, os.ModePerm)
-	if err != nil {
-		fmt.Println("Error creating directory:", err)
-		return data
-	}
-
-	hitDate := data["hitDate"]
-
-	if hitDate == "" {
-		log.Fatal("Hit date is empty. Unable to create filename.")
-	}
-
-	// Set up custom client with timeout
-	client := http.Client{
-		Timeout: 60 * time.Second, // Increase the timeout
-	}
-
-	// Retry mechanism for HTTP request
-	for retry := 0; retry < 3; retry++ {
-		// Create a new HTTP request
-		req, err := http.NewRequest("GET", data["url"], nil)
-		if err != nil {
-			fmt.Println("Error creating HTTP request:", err)
-			continue
-		}
-
-		// Add headers from the `curl` request
-		req.Header.Set("accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
-		req.Header.Set("accept-language", "en-GB,en-US;q=0.9,en;q=0.8,hi;q=0.7")
-		req.Header.Set("cache-control", "no-cache")
-		req.Header.Set("cookie", "pll_language=en; _ga=GA1.1.722567382.1728648220; PHPSESSID=fljmtivh7294k43nutrfub86nq; _ga_BZ6N54FGYB=GS1.1.1728970090.2.1.1728970201.0.0.0")
-		req.Header.Set("pragma", "no-cache")
-		req.Header.Set("priority", "u=0, i")
-		req.Header.Set("sec-ch-ua", `"Google Chrome";v="129", "Not=A?Brand";v="8", "Chromium";v="129"`)
-		req.Header.Set("sec-ch-ua-mobile", "?0")
-		req.Header.Set("sec-ch-ua-platform", `"Windows"`)
-		req.Header.Set("sec-fetch-dest", "document")
-		req.Header.Set("sec-fetch-mode", "navigate")
-		req.Header.Set("sec-fetch-site", "same-origin")
-		req.Header.Set("sec-fetch-user", "?1")
-		req.Header.Set("upgrade-insecure-requests", "1")
-		req.Header.Set("user-agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36")
-
-		// Make the request
-		response, err := client.Do(req)
-		if err != nil {
-			fmt.Println("Error on HTTP request:", err)
-			time.Sleep(5 * time.Second) // Backoff before retrying
-			continue
-		}
-		defer response.Body.Close()
-
-		if response.StatusCode == http.StatusOK {
-			hitDate = strings.ReplaceAll(hitDate, "/", "-")
-			fmt.Println("Response OK, saving causelist...")
-
-			filename := fmt.Sprintf("%s/causelist_pdf_%s.html", dirName, hitDate)
-			fmt.Println("Saving to file:", filename)
-
-			body, err := io.ReadAll(response.Body)
-			if err != nil {
-				log.Fatalf("failed to read response body: %v", err)
-			}
-
-			err = os.WriteFile(filename, body, 0644)
-			if err != nil {
-				log.Fatalf("Failed to create local file: %v", err)
-			}
-
-			fileType := "wb"
-			url, err := upload_on_S3(os.Getenv("S3_BUCKET_NAME"), filename, body, fileType)
-			if err != nil {
-				log.Fatalf("failed to upload to S3: %v", err)
-			}
-			data["s3_path"] = url
-
-			log.Printf("Uploaded successfully, accessible at: %s", url)
-			finalMap, err := FetchAndParseCauselist(data)
-			log.Println("final map :", finalMap)
-			if err != nil {
-				log.Fatalf("Error parsing causelist: %v", err)
-			}
-
-			// Print the map if no error
-			for key, value := range finalMap {
-				fmt.Printf("Key: %s\n", key)
-				fmt.Printf("Date of Hearing: %s\n", value.DateOfHearing)
-				fmt.Printf("Description: %s\n", value.Description)
-				fmt.Printf("PDF Link: %s\n", value.PDFLink)
-			}
-			size := len(finalMap)
-			log.Println("Size of the created map", size)
-			Scraped_data_final = parseCauselistPDFData(finalMap)
-			return data
-		} else {
-			fmt.Printf("Received non-200 response: %d\n", response.StatusCode)
-		}
-
-	}
-
-	fmt.Println("Failed to fetch causelist after 3 retries.")
-	return data
-}
-
-func main() {
-	data := map[string]string{
-		"url":     "https://www.sci.gov.in/cause-list/",
-		"hitDate": "10/16/2024",
-	}
-	key := "86e1b2ce5494c387152f820f453a655bf6b182023656f3f0c720da7d5f72a7f7"
-	err := license.SetMeteredKey(string(key))
-	if err != nil {
-		log.Fatalf("Failed to set metered key: %s", err)
-	}
-	currentTime := time.Now()
-	fmt.Println("Current time :", currentTime)
-	getSupremeCourtCauselistPDF(data)
-	log.Println("causeListMap", causeListMap)
-	saveSCCauseListToRedis(causeListMap)
-	err = saveToCSV("causelist_data.csv", Scraped_data_final)
-	if err != nil {
-		log.Fatalf("Failed to save to CSV: %s", err)
-	}
-
-	fmt.Println("Data saved to causelist_data.csv")
-}
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// supremeCourtID is the registry ID of the Supreme Court of India
+const supremeCourtID = "sci"
+
+// supremeCourt scrapes the cause lists published on sci.gov.in, using the
+// scraper config for its page and search form
+type supremeCourt struct{}
+
+func (supremeCourt) Info() CourtInfo {
+	return CourtInfo{
+		ID:         supremeCourtID,
+		Name:       "Supreme Court of India",
+		Code:       "10",
+		ArchiveDir: "supreme_court",
+		Selectors: PageSelectors{
+			Table:      "table",
+			Header:     "tr:has(th) th",
+			Row:        "tr:has(td)",
+			Cell:       "td",
+			Link:       "a[href$='.pdf' i]",
+			DateColumn: "date",
+		},
+	}
+}
+
+// DiscoverLists reads the cause list page, the search form's result for the
+// date, or an archived page
+func (supremeCourt) DiscoverLists(ctx context.Context, q ListQuery) (map[string]CauseList, error) {
+	if q.Archived != "" {
+		lists, err := parseArchivedCauselist(q.Archived)
+		if err != nil {
+			return nil, &ParseError{Source: q.Archived, Err: err}
+		}
+		return lists, nil
+	}
+
+	data := map[string]string{
+		"url":     appConfig.Scraper.URL,
+		"hitDate": q.Date.Format(hitDateLayout),
+	}
+	if q.Search {
+		data["search"] = "true"
+		data["list_type"] = q.ListType
+		data["court_no"] = q.CourtNo
+		data["bench"] = q.Bench
+	}
+	lists, data, err := getSupremeCourtCauselistPDF(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if data["archive_error"] != "" {
+		return lists, &StorageError{Op: "archive", Key: "cause list page", Err: errors.New(data["archive_error"])}
+	}
+	return lists, nil
+}
+
+// FetchList downloads a cause list PDF
+func (supremeCourt) FetchList(ctx context.Context, pdfID string, causeList CauseList) (*downloadedPDF, error) {
+	return downloadPDF(ctx, causeList.PDFLink)
+}
+
+// ParseList extracts the text of a cause list PDF and parses its entries
+func (supremeCourt) ParseList(pdfID string, pdfData []byte) (*ParsedList, error) {
+	pdfText, err := extractTextFromPDF(pdfData)
+	if err != nil {
+		return nil, err
+	}
+	hearingDate, _ := pdfHeaderDate(pdfText)
+	return &ParsedList{Entries: parseCauselistPDFText(pdfID, pdfText), HearingDate: hearingDate}, nil
+}
+
+var Scraped_data_final []CauseListEntry
+
+// Function to save Scraped_data_final to CSV
+func saveToCSV(fileName string, data []CauseListEntry) error {
+	// Create the CSV file
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %v", err)
+	}
+	defer file.Close()
+
+	// Create a CSV writer
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// Write the CSV header
+	header := []string{"Sno", "CaseNo", "DiaryNo", "CaseNoMap", "JudgeName", "CourtNo",
+		"Section", "Petitioner", "Respondent", "PetitionerAdvocates", "RespondentAdvocates", "IANumbers", "ConnectedTo"}
+	err = writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("failed to write header to CSV: %v", err)
+	}
+
+	// Write the data rows
+	for _, entry := range data {
+		row := []string{entry.Sno, entry.CaseNo, entry.DiaryNo, entry.CaseNoMap, entry.JudgeName(), entry.CourtNo,
+			entry.Section, entry.Petitioner, entry.Respondent, strings.Join(entry.PetitionerAdvocates, "; "),
+			strings.Join(entry.RespondentAdvocates, "; "), strings.Join(entry.IANumbers, "; "), entry.ConnectedTo}
+		err = writer.Write(row)
+		if err != nil {
+			return fmt.Errorf("failed to write row to CSV: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// getSupremeCourtCauselistPDF fetches the cause list page for data["hitDate"],
+// archives it and returns the cause list PDFs found on it. With
+// data["search"] set the page is the result of the search form for the date,
+// narrowed by data["list_type"], data["court_no"] and data["bench"]. Archiving
+// and parsing both work on the fetched body, so a failed upload is recorded in
+// data["archive_error"] and does not stop the parse. Errors are a *FetchError
+// or a *ParseError. Cancelling ctx stops the fetch
+func getSupremeCourtCauselistPDF(ctx context.Context, data map[string]string) (map[string]CauseList, map[string]string, error) {
+	hitDate := data["hitDate"]
+
+	if hitDate == "" {
+		return nil, data, &FetchError{URL: data["url"], Err: errors.New("hit date is empty, unable to create filename")}
+	}
+
+	var body []byte
+	var pageURL string
+	var err error
+	if data["search"] == "true" {
+		date, perr := time.Parse(hitDateLayout, hitDate)
+		if perr != nil {
+			return nil, data, &FetchError{URL: data["url"], Err: fmt.Errorf("invalid hit date %q: %w", hitDate, perr)}
+		}
+		body, pageURL, err = searchCauselistPage(ctx, data["url"], CauseListQuery{
+			Date:     date,
+			ListType: data["list_type"],
+			Court:    data["court_no"],
+			Bench:    data["bench"],
+		})
+	} else {
+		var resp *FetchResponse
+		if resp, err = fetchCauselistPage(ctx, data["url"]); err == nil {
+			body, pageURL = resp.Body, resp.URL
+		}
+	}
+	if err != nil {
+		return nil, data, &FetchError{URL: data["url"], Err: err}
+	}
+
+	key, url, err := archiveCauselistPage(supremeCourt{}.Info().ArchiveDir, hitDate, pageURL, body)
+	if err != nil {
+		log.Printf("Failed to archive causelist page: %v", err)
+		data["archive_error"] = err.Error()
+	} else {
+		data["archive_key"] = key
+		data["s3_path"] = url
+		log.Printf("Uploaded successfully, accessible at: %s", url)
+	}
+
+	lists, err := parseCauselistPage(pageURL, body)
+	if err != nil {
+		return nil, data, &ParseError{Source: "cause list page", Err: err}
+	}
+	return lists, data, nil
+}
+
+// fetchCauselistPage downloads the cause list page within the session; the
+// fetcher retries failed attempts up to fetch.max_attempts
+func fetchCauselistPage(ctx context.Context, url string) (*FetchResponse, error) {
+	// Add headers from the `curl` request
+	header := http.Header{}
+	header.Set("accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
+	header.Set("accept-language", "en-GB,en-US;q=0.9,en;q=0.8,hi;q=0.7")
+	header.Set("cache-control", "no-cache")
+	header.Set("pragma", "no-cache")
+	header.Set("priority", "u=0, i")
+	header.Set("sec-ch-ua", `"Google Chrome";v="129", "Not=A?Brand";v="8", "Chromium";v="129"`)
+	header.Set("sec-ch-ua-mobile", "?0")
+	header.Set("sec-ch-ua-platform", `"Windows"`)
+	header.Set("sec-fetch-dest", "document")
+	header.Set("sec-fetch-mode", "navigate")
+	header.Set("sec-fetch-site", "same-origin")
+	header.Set("sec-fetch-user", "?1")
+	header.Set("upgrade-insecure-requests", "1")
+
+	// The session supplies the cookies; the fetcher retries network errors,
+	// 5xx and 429 with backoff
+	resp, err := sciSession.Get(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("Response OK, reading causelist...")
+	return resp, nil
+}
+
+// archiveCauselistPage saves the cause list page of a court locally and to the
+// blob store, in the court's archive directory, with its checksum, source URL
+// and hearing date. The archive is private; it returns the blob store key and
+// a signed URL to the archived copy
+func archiveCauselistPage(archiveDir, hitDate, sourceURL string, body []byte) (string, string, error) {
+	dirName := "./causelist/pdf/" + archiveDir
+
+	// Create directory if it doesn't exist
+	err := os.MkdirAll(dirName, os.ModePerm)
+	if err != nil {
+		return "", "", &StorageError{Op: "create", Key: dirName, Err: err}
+	}
+
+	sum := sha256.Sum256(body)
+	metadata := map[string]string{
+		blobMetaSHA256:    hex.EncodeToString(sum[:]),
+		blobMetaFetchedAt: time.Now().UTC().Format(time.RFC3339),
+		blobMetaSourceURL: sourceURL,
+	}
+	if date, err := time.Parse(hitDateLayout, hitDate); err == nil {
+		metadata[blobMetaHearingDate] = date.Format("2006-01-02")
+	}
+
+	hitDate = strings.ReplaceAll(hitDate, "/", "-")
+	filename := fmt.Sprintf("%s/causelist_pdf_%s.html", dirName, hitDate)
+	fmt.Println("Saving to file:", filename)
+
+	err = os.WriteFile(filename, body, 0644)
+	if err != nil {
+		return "", "", &StorageError{Op: "write", Key: filename, Err: err}
+	}
+
+	// Uploaded from memory, so the page is written to disk only once
+	key := cleanBlobKey(filename)
+	err = blobStore.Put(context.TODO(), key, bytes.NewReader(body), PutOptions{
+		ContentType: "text/html; charset=utf-8",
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return "", "", &StorageError{Op: "archive", Key: key, Err: err}
+	}
+	url, err := GetSignedURL(key, appConfig.AWS.SignedURLMinutes, false)
+	if err != nil {
+		return "", "", &StorageError{Op: "sign", Key: key, Err: err}
+	}
+	return key, url, nil
+}
+
+// parseCauselistPage extracts the cause list PDFs from the body of the page
+// fetched from pageURL
+func parseCauselistPage(pageURL string, body []byte) (map[string]CauseList, error) {
+	finalMap, err := ParseCauselist(pageURL, string(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing causelist: %w", err)
+	}
+	log.Printf("Supreme Court: %d cause lists on the page", len(finalMap))
+	return finalMap, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestParseCauselistResolvesAgainstPageURL(t *testing.T) {
+	page := `<table>
+<tr><th>Date</th><th>Cause List</th></tr>
+<tr><td>16-10-2024</td><td><a href="../pdf/2024-10-16/M_J_1.pdf">Misc</a></td></tr>
+</table>`
+	lists, err := ParseCauselist("https://www.sci.gov.in/cause-list/search/", page)
+	if err != nil {
+		t.Fatalf("ParseCauselist: %v", err)
+	}
+	got, ok := lists["2024-10-16/M_J_1"]
+	if !ok {
+		t.Fatalf("cause list missing, got %v", sortedKeys(lists))
+	}
+	if want := "https://www.sci.gov.in/cause-list/pdf/2024-10-16/M_J_1.pdf"; got.PDFLink != want {
+		t.Errorf("link %q, want %q", got.PDFLink, want)
+	}
+}
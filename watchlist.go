@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WatchlistItem is a tracked matter, identified by a case number or a diary number
+type WatchlistItem struct {
+	CaseNo  string   `yaml:"case_no,omitempty" json:"case_no,omitempty"`   // e.g. "SLP(C) No. 123/2024"
+	DiaryNo string   `yaml:"diary_no,omitempty" json:"diary_no,omitempty"` // e.g. "12345/2024"
+	Owner   string   `yaml:"owner,omitempty" json:"owner,omitempty"`       // Who to tell when it is listed
+	Tags    []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// key returns the normalized case or diary number the item is matched on, or ""
+func (w WatchlistItem) key() string {
+	if w.CaseNo != "" {
+		if caseKey := normalizeCaseNumber(w.CaseNo); caseKey != "" {
+			return "case:" + caseKey
+		}
+		return ""
+	}
+	if diaryKey := normalizeDiaryNumber(w.DiaryNo); diaryKey != "" {
+		return "diary:" + diaryKey
+	}
+	return ""
+}
+
+// label returns the number the item was added with
+func (w WatchlistItem) label() string {
+	if w.CaseNo != "" {
+		return w.CaseNo
+	}
+	return "Diary No. " + w.DiaryNo
+}
+
+// WatchlistStore keeps the tracked matters
+type WatchlistStore interface {
+	List() ([]WatchlistItem, error)
+	Add(item WatchlistItem) error    // Replaces an item with the same case or diary number
+	Remove(item WatchlistItem) error // Removes the item with the same case or diary number
+}
+
+// watchlist is nil when no watchlist source is configured
+var watchlist WatchlistStore
+
+// newWatchlistStore creates the WatchlistStore selected in the watchlist config
+func newWatchlistStore(cfg WatchlistConfig) (WatchlistStore, error) {
+	switch cfg.Source {
+	case "":
+		return nil, nil
+	case "file":
+		return NewFileWatchlistStore(cfg.File), nil
+	case "database":
+		store := causeListStore
+		if store == nil {
+			var err error
+			if store, err = openCauseListStore(); err != nil {
+				return nil, err
+			}
+		}
+		return NewDBWatchlistStore(store), nil
+	default:
+		return nil, fmt.Errorf("unknown watchlist source %q", cfg.Source)
+	}
+}
+
+// FileWatchlistStore keeps the watchlist in a YAML file, see watchlist.example.yaml
+type FileWatchlistStore struct {
+	path string
+}
+
+// watchlistFile is the content of a watchlist YAML file
+type watchlistFile struct {
+	Items []WatchlistItem `yaml:"items"`
+}
+
+// NewFileWatchlistStore creates a FileWatchlistStore; the file is created on the first Add
+func NewFileWatchlistStore(path string) *FileWatchlistStore {
+	return &FileWatchlistStore{path: path}
+}
+
+// List reads every item of the file. A missing file is an empty watchlist
+func (f *FileWatchlistStore) List() ([]WatchlistItem, error) {
+	raw, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+	var file watchlistFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse watchlist %s: %w", f.path, err)
+	}
+	return file.Items, nil
+}
+
+// Add writes the item to the file
+func (f *FileWatchlistStore) Add(item WatchlistItem) error {
+	key := item.key()
+	if key == "" {
+		return fmt.Errorf("cannot parse %q", item.label())
+	}
+	items, err := f.List()
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if items[i].key() == key {
+			items[i] = item
+			return f.write(items)
+		}
+	}
+	return f.write(append(items, item))
+}
+
+// Remove deletes the item from the file
+func (f *FileWatchlistStore) Remove(item WatchlistItem) error {
+	key := item.key()
+	items, err := f.List()
+	if err != nil {
+		return err
+	}
+	kept := items[:0]
+	for _, existing := range items {
+		if existing.key() != key {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(items) {
+		return fmt.Errorf("%s is not on the watchlist", item.label())
+	}
+	return f.write(kept)
+}
+
+func (f *FileWatchlistStore) write(items []WatchlistItem) error {
+	out, err := yaml.Marshal(watchlistFile{Items: items})
+	if err != nil {
+		return fmt.Errorf("failed to encode watchlist: %w", err)
+	}
+	if err := os.WriteFile(f.path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write watchlist: %w", err)
+	}
+	return nil
+}
+
+// WatchlistRecord is a tracked matter kept in the database
+type WatchlistRecord struct {
+	ID        uint   `gorm:"primaryKey"`
+	MatchKey  string `gorm:"size:255;not null;uniqueIndex"` // See WatchlistItem.key
+	CaseNo    string `gorm:"size:255"`
+	DiaryNo   string `gorm:"size:64"`
+	Owner     string `gorm:"size:255;index"`
+	Tags      string // Comma separated
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName keeps the table name stable if the struct is renamed
+func (WatchlistRecord) TableName() string {
+	return "watchlist_items"
+}
+
+// DBWatchlistStore keeps the watchlist in the watchlist_items table
+type DBWatchlistStore struct {
+	db *gorm.DB
+}
+
+// NewDBWatchlistStore creates a DBWatchlistStore on the database of store
+func NewDBWatchlistStore(store *CauseListStore) *DBWatchlistStore {
+	return &DBWatchlistStore{db: store.db}
+}
+
+// List returns every item ordered by owner
+func (d *DBWatchlistStore) List() ([]WatchlistItem, error) {
+	var records []WatchlistRecord
+	if err := d.db.Order("owner, id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read watchlist: %w", err)
+	}
+	items := make([]WatchlistItem, 0, len(records))
+	for _, record := range records {
+		items = append(items, WatchlistItem{
+			CaseNo:  record.CaseNo,
+			DiaryNo: record.DiaryNo,
+			Owner:   record.Owner,
+			Tags:    splitList(record.Tags),
+		})
+	}
+	return items, nil
+}
+
+// Add upserts the item on its normalized case or diary number
+func (d *DBWatchlistStore) Add(item WatchlistItem) error {
+	key := item.key()
+	if key == "" {
+		return fmt.Errorf("cannot parse %q", item.label())
+	}
+	record := WatchlistRecord{
+		MatchKey: key,
+		CaseNo:   item.CaseNo,
+		DiaryNo:  item.DiaryNo,
+		Owner:    item.Owner,
+		Tags:     strings.Join(item.Tags, ","),
+	}
+	err := d.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "match_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"case_no", "diary_no", "owner", "tags", "updated_at"}),
+	}).Create(&record).Error
+	if err != nil {
+		return fmt.Errorf("failed to save watchlist item: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the item with the same case or diary number
+func (d *DBWatchlistStore) Remove(item WatchlistItem) error {
+	result := d.db.Where("match_key = ?", item.key()).Delete(&WatchlistRecord{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove watchlist item: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%s is not on the watchlist", item.label())
+	}
+	return nil
+}
+
+// WatchlistMatch is a tracked matter found in a cause list
+type WatchlistMatch struct {
+	Item    WatchlistItem `json:"item"`
+	Listing CaseListing   `json:"listing"`
+}
+
+// WatchlistReport lists the tracked matters found in one run
+type WatchlistReport struct {
+	DateOfHearing string           `json:"date_of_hearing"`
+	Watched       int              `json:"watched"` // Number of items on the watchlist
+	Matches       []WatchlistMatch `json:"matches"`
+}
+
+// matchWatchlist finds every watchlist item among the parsed entries
+func matchWatchlist(hearingDate string, items []WatchlistItem, causeLists map[string]CauseList, entries []CauseListEntry) WatchlistReport {
+	byKey := make(map[string][]CaseListing)
+	for _, entry := range entries {
+		causeList, ok := causeLists[entry.PDFID]
+		if !ok {
+			continue
+		}
+		listing := newCaseListing(entry, causeList)
+		for _, caseKey := range entryCaseNumberKeys(entry) {
+			byKey["case:"+caseKey] = append(byKey["case:"+caseKey], listing)
+		}
+		if diaryKey := normalizeDiaryNumber(entry.DiaryNo); diaryKey != "" {
+			byKey["diary:"+diaryKey] = append(byKey["diary:"+diaryKey], listing)
+		}
+	}
+
+	report := WatchlistReport{DateOfHearing: hearingDate, Watched: len(items)}
+	for _, item := range items {
+		key := item.key()
+		if key == "" {
+			log.Printf("Skipping unparseable watchlist item %q", item.label())
+			continue
+		}
+		for _, listing := range byKey[key] {
+			report.Matches = append(report.Matches, WatchlistMatch{Item: item, Listing: listing})
+		}
+	}
+	sort.SliceStable(report.Matches, func(i, j int) bool {
+		a, b := report.Matches[i].Listing, report.Matches[j].Listing
+		if a.ListType != b.ListType {
+			return a.ListType < b.ListType
+		}
+		if a.CourtNo != b.CourtNo {
+			return compareItemNumbers(a.CourtNo, b.CourtNo) < 0
+		}
+		return compareItemNumbers(a.Sno, b.Sno) < 0
+	})
+	return report
+}
+
+// Text renders the report as a plain text table
+func (r WatchlistReport) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d of %d watched matters listed on %s\n\n", len(r.Matches), r.Watched, r.DateOfHearing)
+	w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CASE\tOWNER\tLIST\tSECTION\tCOURT\tBENCH\tITEM\tTAGS")
+	for _, m := range r.Matches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", m.Item.label(), m.Item.Owner, m.Listing.ListType,
+			m.Listing.Section, m.Listing.CourtNo, m.Listing.JudgeName(), m.Listing.Sno, strings.Join(m.Item.Tags, ","))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// checkWatchlist reports the watched matters among the entries of a run and
+// sends the report through every configured notifier
+func checkWatchlist(ctx context.Context, hearingDate string, causeLists map[string]CauseList, entries []CauseListEntry) error {
+	if watchlist == nil {
+		return nil
+	}
+	items, err := watchlist.List()
+	if err != nil {
+		return err
+	}
+	report := matchWatchlist(hearingDate, items, causeLists, entries)
+	if len(report.Matches) == 0 {
+		fmt.Printf("No watched matters listed on %s\n", hearingDate)
+		return nil
+	}
+	return dispatchReport(ctx, report)
+}
+
+// runWatchlist implements "watchlist list", "watchlist add" and "watchlist remove"
+func runWatchlist(args []string) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "add" && args[0] != "remove") {
+		fmt.Fprintln(os.Stderr, "Usage: golang-scrappers watchlist list|add|remove [flags]")
+		return exitUsage
+	}
+
+	var item WatchlistItem
+	fs := flag.NewFlagSet("watchlist "+args[0], flag.ContinueOnError)
+	cf := registerConfigFlags(fs)
+	if args[0] != "list" {
+		fs.StringVar(&item.CaseNo, "case", "", "case number, e.g. \"SLP(C) No. 123/2024\"")
+		fs.StringVar(&item.DiaryNo, "diary", "", "diary number, e.g. 12345/2024")
+	}
+	if args[0] == "add" {
+		fs.StringVar(&item.Owner, "owner", "", "who is told when the matter is listed")
+		fs.Func("tags", "comma separated tags", func(v string) error {
+			item.Tags = splitList(v)
+			return nil
+		})
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if args[0] != "list" && (item.CaseNo == "") == (item.DiaryNo == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --case or --diary is required")
+		return exitUsage
+	}
+
+	c, err := cf.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if c.Watchlist.Source == "" {
+		fmt.Fprintln(os.Stderr, "watchlist.source (WATCHLIST_SOURCE or --watchlist) is required")
+		return exitUsage
+	}
+	appConfig = c
+
+	store, err := newWatchlistStore(appConfig.Watchlist)
+	if err != nil {
+		log.Printf("Failed to open watchlist: %v", err)
+		return exitFailure
+	}
+
+	switch args[0] {
+	case "add":
+		err = store.Add(item)
+	case "remove":
+		err = store.Remove(item)
+	default:
+		var items []WatchlistItem
+		if items, err = store.List(); err == nil {
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CASE\tOWNER\tTAGS")
+			for _, i := range items {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", i.label(), i.Owner, strings.Join(i.Tags, ","))
+			}
+			w.Flush()
+		}
+	}
+	if err != nil {
+		log.Printf("Watchlist %s failed: %v", args[0], err)
+		return exitFailure
+	}
+	return exitOK
+}